@@ -0,0 +1,129 @@
+package mco
+
+import (
+	"fmt"
+
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/msg"
+	mcv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContainerRuntimeConfigBuilder provides struct for ContainerRuntimeConfig object which contains connection to
+// cluster and ContainerRuntimeConfig definitions.
+type ContainerRuntimeConfigBuilder struct {
+	// ContainerRuntimeConfig definition. Used to create ContainerRuntimeConfig object with minimum set of required
+	// elements.
+	Definition *mcv1.ContainerRuntimeConfig
+	// Created ContainerRuntimeConfig object on the cluster.
+	Object *mcv1.ContainerRuntimeConfig
+	// api client to interact with the cluster.
+	apiClient *clients.Settings
+	// errorMsg is processed before ContainerRuntimeConfig object is created.
+	errorMsg string
+}
+
+// PullContainerRuntimeConfig pulls existing containerruntimeconfig from cluster.
+func PullContainerRuntimeConfig(apiClient *clients.Settings, name string) (*ContainerRuntimeConfigBuilder, error) {
+	verbose().Infof("Pulling existing containerruntimeconfig name %s from cluster", name)
+
+	builder := ContainerRuntimeConfigBuilder{
+		apiClient: apiClient,
+		Definition: &mcv1.ContainerRuntimeConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		},
+	}
+
+	if name == "" {
+		verbose().Infof("The name of the containerruntimeconfig is empty")
+
+		builder.errorMsg = "containerruntimeconfig 'name' cannot be empty"
+	}
+
+	exists, err := builder.exists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull containerruntimeconfig %s: %w", name, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("containerruntimeconfig object %s doesn't exist", name)
+	}
+
+	builder.Definition = builder.Object
+
+	return &builder, nil
+}
+
+// Exists checks whether the given containerruntimeconfig exists. A failed request, e.g. a timeout, is reported as not
+// existing.
+func (builder *ContainerRuntimeConfigBuilder) Exists() bool {
+	exists, err := builder.exists()
+	if err != nil {
+		verbose().Infof("Failed to check if the ContainerRuntimeConfig object exists: %v", err)
+	}
+
+	return exists
+}
+
+// exists fetches the ContainerRuntimeConfig object into builder.Object. A missing object is reported without error,
+// any other error of the request is returned and builder.Object is reset.
+func (builder *ContainerRuntimeConfigBuilder) exists() (bool, error) {
+	if valid, err := builder.validate(); !valid {
+		return false, err
+	}
+
+	verbose().Infof("Checking if the ContainerRuntimeConfig object %s exists", builder.Definition.Name)
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	object, err := builder.apiClient.ContainerRuntimeConfigs().Get(ctx, builder.Definition.Name, metav1.GetOptions{})
+	if err != nil {
+		builder.Object = nil
+
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get ContainerRuntimeConfig %s: %w", builder.Definition.Name, err)
+	}
+
+	builder.Object = object
+
+	return true, nil
+}
+
+// validate will check that the builder and builder definition are properly initialized before
+// accessing any member fields.
+func (builder *ContainerRuntimeConfigBuilder) validate() (bool, error) {
+	resourceCRD := "ContainerRuntimeConfig"
+
+	if builder == nil {
+		verbose().Infof("The %s builder is uninitialized", resourceCRD)
+
+		return false, fmt.Errorf("error: received nil %s builder", resourceCRD)
+	}
+
+	if builder.Definition == nil {
+		verbose().Infof("The %s is undefined", resourceCRD)
+
+		builder.errorMsg = msg.UndefinedCrdObjectErrString(resourceCRD)
+	}
+
+	if builder.apiClient == nil {
+		verbose().Infof("The %s builder apiclient is nil", resourceCRD)
+
+		builder.errorMsg = fmt.Sprintf("%s builder cannot have nil apiClient", resourceCRD)
+	}
+
+	if builder.errorMsg != "" {
+		verbose().Infof("The %s builder has error message: %s", resourceCRD, builder.errorMsg)
+
+		return false, fmt.Errorf(builder.errorMsg)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,128 @@
+package mco
+
+import (
+	"fmt"
+
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/msg"
+	mcv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KubeletConfigBuilder provides struct for KubeletConfig object which contains connection to cluster
+// and KubeletConfig definitions.
+type KubeletConfigBuilder struct {
+	// KubeletConfig definition. Used to create KubeletConfig object with minimum set of required elements.
+	Definition *mcv1.KubeletConfig
+	// Created KubeletConfig object on the cluster.
+	Object *mcv1.KubeletConfig
+	// api client to interact with the cluster.
+	apiClient *clients.Settings
+	// errorMsg is processed before KubeletConfig object is created.
+	errorMsg string
+}
+
+// PullKubeletConfig pulls existing kubeletconfig from cluster.
+func PullKubeletConfig(apiClient *clients.Settings, name string) (*KubeletConfigBuilder, error) {
+	verbose().Infof("Pulling existing kubeletconfig name %s from cluster", name)
+
+	builder := KubeletConfigBuilder{
+		apiClient: apiClient,
+		Definition: &mcv1.KubeletConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		},
+	}
+
+	if name == "" {
+		verbose().Infof("The name of the kubeletconfig is empty")
+
+		builder.errorMsg = "kubeletconfig 'name' cannot be empty"
+	}
+
+	exists, err := builder.exists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull kubeletconfig %s: %w", name, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("kubeletconfig object %s doesn't exist", name)
+	}
+
+	builder.Definition = builder.Object
+
+	return &builder, nil
+}
+
+// Exists checks whether the given kubeletconfig exists. A failed request, e.g. a timeout, is reported as not
+// existing.
+func (builder *KubeletConfigBuilder) Exists() bool {
+	exists, err := builder.exists()
+	if err != nil {
+		verbose().Infof("Failed to check if the KubeletConfig object exists: %v", err)
+	}
+
+	return exists
+}
+
+// exists fetches the KubeletConfig object into builder.Object. A missing object is reported without error,
+// any other error of the request is returned and builder.Object is reset.
+func (builder *KubeletConfigBuilder) exists() (bool, error) {
+	if valid, err := builder.validate(); !valid {
+		return false, err
+	}
+
+	verbose().Infof("Checking if the KubeletConfig object %s exists", builder.Definition.Name)
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	object, err := builder.apiClient.KubeletConfigs().Get(ctx, builder.Definition.Name, metav1.GetOptions{})
+	if err != nil {
+		builder.Object = nil
+
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get KubeletConfig %s: %w", builder.Definition.Name, err)
+	}
+
+	builder.Object = object
+
+	return true, nil
+}
+
+// validate will check that the builder and builder definition are properly initialized before
+// accessing any member fields.
+func (builder *KubeletConfigBuilder) validate() (bool, error) {
+	resourceCRD := "KubeletConfig"
+
+	if builder == nil {
+		verbose().Infof("The %s builder is uninitialized", resourceCRD)
+
+		return false, fmt.Errorf("error: received nil %s builder", resourceCRD)
+	}
+
+	if builder.Definition == nil {
+		verbose().Infof("The %s is undefined", resourceCRD)
+
+		builder.errorMsg = msg.UndefinedCrdObjectErrString(resourceCRD)
+	}
+
+	if builder.apiClient == nil {
+		verbose().Infof("The %s builder apiclient is nil", resourceCRD)
+
+		builder.errorMsg = fmt.Sprintf("%s builder cannot have nil apiClient", resourceCRD)
+	}
+
+	if builder.errorMsg != "" {
+		verbose().Infof("The %s builder has error message: %s", resourceCRD, builder.errorMsg)
+
+		return false, fmt.Errorf(builder.errorMsg)
+	}
+
+	return true, nil
+}
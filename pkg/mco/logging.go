@@ -0,0 +1,26 @@
+package mco
+
+import (
+	"sync/atomic"
+
+	"github.com/golang/glog"
+)
+
+// quiet suppresses the log output of the mco package builders when set.
+var quiet atomic.Bool
+
+// SetQuiet suppresses, or restores, the verbose log output of all builders of the mco package, e.g. to keep the
+// output of verbose test runs readable. Logging is enabled by default.
+func SetQuiet(enabled bool) {
+	quiet.Store(enabled)
+}
+
+// verbose returns whether the verbose log output of the caller is enabled, following the glog -v and -vmodule
+// flags unless the package is quiet.
+func verbose() glog.Verbose {
+	if quiet.Load() {
+		return false
+	}
+
+	return glog.VDepth(1, 100)
+}
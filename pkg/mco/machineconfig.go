@@ -1,15 +1,21 @@
 package mco
 
 import (
-	"context"
 	"fmt"
+	"sort"
+	"strings"
 
-	"github.com/golang/glog"
 	"github.com/openshift-kni/eco-goinfra/pkg/clients"
 	"github.com/openshift-kni/eco-goinfra/pkg/msg"
 	mcv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	machineConfigRoleLabel = "machineconfiguration.openshift.io/role"
+	renderedConfigPrefix   = "rendered-"
 )
 
 // MCBuilder provides struct for MachineConfig Object which contains connection to cluster
@@ -31,7 +37,7 @@ type MCAdditionalOptions func(builder *MCBuilder) (*MCBuilder, error)
 // NewMCBuilder provides struct for MachineConfig object which contains connection to cluster
 // and MachineConfig definition.
 func NewMCBuilder(apiClient *clients.Settings, name string) *MCBuilder {
-	glog.V(100).Infof("Initializing new MCBuilder structure with following params: %s", name)
+	verbose().Infof("Initializing new MCBuilder structure with following params: %s", name)
 
 	builder := MCBuilder{
 		apiClient: apiClient,
@@ -43,7 +49,7 @@ func NewMCBuilder(apiClient *clients.Settings, name string) *MCBuilder {
 	}
 
 	if name == "" {
-		glog.V(100).Infof("The name of the MachineConfig is empty")
+		verbose().Infof("The name of the MachineConfig is empty")
 
 		builder.errorMsg = "MachineConfig 'name' cannot be empty"
 	}
@@ -53,7 +59,7 @@ func NewMCBuilder(apiClient *clients.Settings, name string) *MCBuilder {
 
 // PullMachineConfig fetches existing machineconfig from cluster.
 func PullMachineConfig(apiClient *clients.Settings, name string) (*MCBuilder, error) {
-	glog.V(100).Infof("Pulling existing machineconfig name %s from cluster", name)
+	verbose().Infof("Pulling existing machineconfig name %s from cluster", name)
 
 	builder := MCBuilder{
 		apiClient: apiClient,
@@ -65,7 +71,7 @@ func PullMachineConfig(apiClient *clients.Settings, name string) (*MCBuilder, er
 	}
 
 	if name == "" {
-		glog.V(100).Infof("The name of the machineconfig is empty")
+		verbose().Infof("The name of the machineconfig is empty")
 
 		builder.errorMsg = "machineconfig 'name' cannot be empty"
 	}
@@ -79,18 +85,137 @@ func PullMachineConfig(apiClient *clients.Settings, name string) (*MCBuilder, er
 	return &builder, nil
 }
 
+// PullMachineConfigByRole fetches all machineconfigs labeled with the given role from cluster, sorted by name.
+func PullMachineConfigByRole(apiClient *clients.Settings, role string) ([]*MCBuilder, error) {
+	verbose().Infof("Pulling existing machineconfigs with role %s from cluster", role)
+
+	if apiClient == nil {
+		verbose().Infof("The apiClient is empty")
+
+		return nil, fmt.Errorf("machineconfig 'apiClient' cannot be empty")
+	}
+
+	if role == "" {
+		verbose().Infof("The role of the machineconfig is empty")
+
+		return nil, fmt.Errorf("machineconfig 'role' cannot be empty")
+	}
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	mcList, err := apiClient.MachineConfigs().List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{machineConfigRoleLabel: role}.String(),
+	})
+	if err != nil {
+		verbose().Infof("Failed to list machineconfigs with role %s due to %s", role, err.Error())
+
+		return nil, err
+	}
+
+	sort.Slice(mcList.Items, func(i, j int) bool {
+		return mcList.Items[i].Name < mcList.Items[j].Name
+	})
+
+	var mcObjects []*MCBuilder
+
+	for _, machineConfig := range mcList.Items {
+		copiedMachineConfig := machineConfig
+		mcBuilder := &MCBuilder{
+			apiClient:  apiClient,
+			Object:     &copiedMachineConfig,
+			Definition: &copiedMachineConfig,
+		}
+
+		mcObjects = append(mcObjects, mcBuilder)
+	}
+
+	return mcObjects, nil
+}
+
+// ListOrphanMachineConfigs fetches all machineconfigs from cluster that are not selected by the
+// machineConfigSelector of any machineconfigpool, sorted by name. Rendered machineconfigs are excluded.
+func ListOrphanMachineConfigs(apiClient *clients.Settings) ([]*MCBuilder, error) {
+	verbose().Infof("Listing machineconfigs not selected by any machineconfigpool")
+
+	if apiClient == nil {
+		verbose().Infof("The apiClient is empty")
+
+		return nil, fmt.Errorf("machineconfig 'apiClient' cannot be empty")
+	}
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	mcList, err := apiClient.MachineConfigs().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		verbose().Infof("Failed to list machineconfigs due to %s", err.Error())
+
+		return nil, err
+	}
+
+	mcpList, err := apiClient.MachineConfigPools().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		verbose().Infof("Failed to list machineconfigpools due to %s", err.Error())
+
+		return nil, err
+	}
+
+	sort.Slice(mcList.Items, func(i, j int) bool {
+		return mcList.Items[i].Name < mcList.Items[j].Name
+	})
+
+	var mcObjects []*MCBuilder
+
+	for index := range mcList.Items {
+		machineConfig := &mcList.Items[index]
+
+		if strings.HasPrefix(machineConfig.Name, renderedConfigPrefix) {
+			continue
+		}
+
+		orphan := true
+
+		for _, mcp := range mcpList.Items {
+			selected, err := selectsMachineConfig(&mcp.Spec, machineConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to match machineconfigpool %s selector: %w", mcp.Name, err)
+			}
+
+			if selected {
+				orphan = false
+
+				break
+			}
+		}
+
+		if orphan {
+			mcObjects = append(mcObjects, &MCBuilder{
+				apiClient:  apiClient,
+				Object:     machineConfig,
+				Definition: machineConfig,
+			})
+		}
+	}
+
+	return mcObjects, nil
+}
+
 // Create generates a machineconfig in the cluster and stores the created object in struct.
 func (builder *MCBuilder) Create() (*MCBuilder, error) {
 	if valid, err := builder.validate(); !valid {
 		return builder, err
 	}
 
-	glog.V(100).Infof("Creating MachineConfig %s", builder.Definition.Name)
+	verbose().Infof("Creating MachineConfig %s", builder.Definition.Name)
 
 	var err error
 	if !builder.Exists() {
+		ctx, cancel := newOperationContext()
+		defer cancel()
+
 		builder.Object, err = builder.apiClient.MachineConfigs().Create(
-			context.TODO(), builder.Definition, metav1.CreateOptions{})
+			ctx, builder.Definition, metav1.CreateOptions{})
 	}
 
 	return builder, err
@@ -102,14 +227,17 @@ func (builder *MCBuilder) Delete() error {
 		return err
 	}
 
-	glog.V(100).Infof("Deleting the MachineConfig object %s", builder.Definition.Name)
+	verbose().Infof("Deleting the MachineConfig object %s", builder.Definition.Name)
 
 	if !builder.Exists() {
 		return fmt.Errorf("MachineConfig cannot be deleted because it does not exist")
 	}
 
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
 	err := builder.apiClient.MachineConfigs().Delete(
-		context.TODO(), builder.Object.Name, metav1.DeleteOptions{})
+		ctx, builder.Object.Name, metav1.DeleteOptions{})
 
 	if err != nil {
 		return fmt.Errorf("cannot delete MachineConfig: %w", err)
@@ -126,28 +254,55 @@ func (builder *MCBuilder) Update() (*MCBuilder, error) {
 		return builder, err
 	}
 
-	glog.V(100).Infof("Updating machineconfig %s", builder.Definition.Name)
+	verbose().Infof("Updating machineconfig %s", builder.Definition.Name)
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
 
 	var err error
 	builder.Object, err = builder.apiClient.MachineConfigs().Update(
-		context.TODO(), builder.Definition, metav1.UpdateOptions{})
+		ctx, builder.Definition, metav1.UpdateOptions{})
 
 	return builder, err
 }
 
-// Exists checks whether the given machineconfig exists.
+// Exists checks whether the given machineconfig exists. A failed request, e.g. a timeout, is reported as not
+// existing.
 func (builder *MCBuilder) Exists() bool {
-	if valid, _ := builder.validate(); !valid {
-		return false
+	exists, err := builder.exists()
+	if err != nil {
+		verbose().Infof("Failed to check if the MachineConfig object exists: %v", err)
 	}
 
-	glog.V(100).Infof("Checking if the MachineConfig object %s exists", builder.Definition.Name)
+	return exists
+}
 
-	var err error
-	builder.Object, err = builder.apiClient.MachineConfigs().Get(
-		context.Background(), builder.Definition.Name, metav1.GetOptions{})
+// exists fetches the MachineConfig object into builder.Object. A missing object is reported without error,
+// any other error of the request is returned and builder.Object is reset.
+func (builder *MCBuilder) exists() (bool, error) {
+	if valid, err := builder.validate(); !valid {
+		return false, err
+	}
+
+	verbose().Infof("Checking if the MachineConfig object %s exists", builder.Definition.Name)
 
-	return err == nil || !k8serrors.IsNotFound(err)
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	object, err := builder.apiClient.MachineConfigs().Get(ctx, builder.Definition.Name, metav1.GetOptions{})
+	if err != nil {
+		builder.Object = nil
+
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get MachineConfig %s: %w", builder.Definition.Name, err)
+	}
+
+	builder.Object = object
+
+	return true, nil
 }
 
 // WithLabel redefines machineconfig definition with the given label.
@@ -156,10 +311,10 @@ func (builder *MCBuilder) WithLabel(key, value string) *MCBuilder {
 		return builder
 	}
 
-	glog.V(100).Infof("Labeling the machineconfig %s with %s=%s", builder.Definition.Name, key, value)
+	verbose().Infof("Labeling the machineconfig %s with %s=%s", builder.Definition.Name, key, value)
 
 	if key == "" {
-		glog.V(100).Infof("The key can't be empty")
+		verbose().Infof("The key can't be empty")
 
 		builder.errorMsg = "'key' cannot be empty"
 
@@ -181,14 +336,14 @@ func (builder *MCBuilder) WithOptions(options ...MCAdditionalOptions) *MCBuilder
 		return builder
 	}
 
-	glog.V(100).Infof("Setting machineconfig additional options")
+	verbose().Infof("Setting machineconfig additional options")
 
 	for _, option := range options {
 		if option != nil {
 			builder, err := option(builder)
 
 			if err != nil {
-				glog.V(100).Infof("Error occurred in mutation function")
+				verbose().Infof("Error occurred in mutation function")
 
 				builder.errorMsg = err.Error()
 
@@ -207,14 +362,14 @@ func (builder *MCBuilder) WithKernelArguments(kernelArgs []string) *MCBuilder {
 	}
 
 	if len(kernelArgs) == 0 {
-		glog.V(100).Infof("The kernelArgs can't be empty")
+		verbose().Infof("The kernelArgs can't be empty")
 
 		builder.errorMsg = "'kernelArgs' cannot be empty"
 
 		return builder
 	}
 
-	glog.V(100).Infof("Setting KernelArguments: %v", kernelArgs)
+	verbose().Infof("Setting KernelArguments: %v", kernelArgs)
 
 	builder.Definition.Spec.KernelArguments = kernelArgs
 
@@ -228,14 +383,14 @@ func (builder *MCBuilder) WithExtensions(extensions []string) *MCBuilder {
 	}
 
 	if len(extensions) == 0 {
-		glog.V(100).Infof("The extensions can't be empty")
+		verbose().Infof("The extensions can't be empty")
 
 		builder.errorMsg = "'extensions' cannot be empty"
 
 		return builder
 	}
 
-	glog.V(100).Infof("Setting Extensions: %v", extensions)
+	verbose().Infof("Setting Extensions: %v", extensions)
 
 	builder.Definition.Spec.Extensions = extensions
 
@@ -248,7 +403,7 @@ func (builder *MCBuilder) WithFIPS(fips bool) *MCBuilder {
 		return builder
 	}
 
-	glog.V(100).Infof("Setting FIPS: %v", fips)
+	verbose().Infof("Setting FIPS: %v", fips)
 
 	builder.Definition.Spec.FIPS = fips
 
@@ -262,14 +417,14 @@ func (builder *MCBuilder) WithKernelType(kernelType string) *MCBuilder {
 	}
 
 	if kernelType == "" {
-		glog.V(100).Infof("The kernelType can't be empty")
+		verbose().Infof("The kernelType can't be empty")
 
 		builder.errorMsg = "'kernelType' cannot be empty"
 
 		return builder
 	}
 
-	glog.V(100).Infof("Setting KernelType: %v", kernelType)
+	verbose().Infof("Setting KernelType: %v", kernelType)
 
 	builder.Definition.Spec.KernelType = kernelType
 
@@ -280,25 +435,25 @@ func (builder *MCBuilder) validate() (bool, error) {
 	resourceCRD := "MachineConfig"
 
 	if builder == nil {
-		glog.V(100).Infof("The %s builder is uninitialized", resourceCRD)
+		verbose().Infof("The %s builder is uninitialized", resourceCRD)
 
 		return false, fmt.Errorf("error: received nil %s builder", resourceCRD)
 	}
 
 	if builder.Definition == nil {
-		glog.V(100).Infof("The %s is undefined", resourceCRD)
+		verbose().Infof("The %s is undefined", resourceCRD)
 
 		builder.errorMsg = msg.UndefinedCrdObjectErrString(resourceCRD)
 	}
 
 	if builder.apiClient == nil {
-		glog.V(100).Infof("The %s builder apiclient is nil", resourceCRD)
+		verbose().Infof("The %s builder apiclient is nil", resourceCRD)
 
 		builder.errorMsg = fmt.Sprintf("%s builder cannot have nil apiClient", resourceCRD)
 	}
 
 	if builder.errorMsg != "" {
-		glog.V(100).Infof("The %s builder has error message: %s", resourceCRD, builder.errorMsg)
+		verbose().Infof("The %s builder has error message: %s", resourceCRD, builder.errorMsg)
 
 		return false, fmt.Errorf(builder.errorMsg)
 	}
@@ -0,0 +1,410 @@
+package mco
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/msg"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	machineOSConfigKind = "MachineOSConfig"
+	// jobImageBuilderType builds the layered image in a Job, the only image builder type supported by the MCO.
+	jobImageBuilderType = "Job"
+	// noArchContainerfile marks a Containerfile as applying to every architecture.
+	noArchContainerfile = "NoArch"
+	// machineOSBuildSucceeded and machineOSBuildFailed are the terminal MachineOSBuild condition types.
+	machineOSBuildSucceeded = "Succeeded"
+	machineOSBuildFailed    = "Failed"
+)
+
+// machineOSConfigGVR is the resource of the MachineOSConfig objects. The vendored machine-config-operator API
+// predates on-cluster layering, so MachineOSConfig objects are handled through the dynamic client.
+var machineOSConfigGVR = schema.GroupVersionResource{
+	Group: "machineconfiguration.openshift.io", Version: "v1", Resource: "machineosconfigs"}
+
+// machineOSBuildGVR is the resource of the MachineOSBuild objects created for a MachineOSConfig.
+var machineOSBuildGVR = schema.GroupVersionResource{
+	Group: "machineconfiguration.openshift.io", Version: "v1", Resource: "machineosbuilds"}
+
+// MachineOSConfigBuilder provides struct for MachineOSConfig object which contains connection to cluster
+// and MachineOSConfig definitions.
+type MachineOSConfigBuilder struct {
+	// MachineOSConfig definition. Used to create MachineOSConfig object with minimum set of required elements.
+	Definition *unstructured.Unstructured
+	// Created MachineOSConfig object on the cluster.
+	Object *unstructured.Unstructured
+	// api client to interact with the cluster.
+	apiClient *clients.Settings
+	// errorMsg is processed before MachineOSConfig object is created.
+	errorMsg string
+}
+
+// NewMachineOSConfigBuilder method creates new instance of builder.
+func NewMachineOSConfigBuilder(apiClient *clients.Settings, name string) *MachineOSConfigBuilder {
+	verbose().Infof(
+		"Initializing new MachineOSConfigBuilder structure with the following params: %s", name)
+
+	builder := &MachineOSConfigBuilder{
+		apiClient:  apiClient,
+		Definition: newMachineOSConfigDefinition(name),
+	}
+
+	if name == "" {
+		verbose().Infof("The name of the MachineOSConfig is empty")
+
+		builder.errorMsg = "MachineOSConfig 'name' cannot be empty"
+
+		return builder
+	}
+
+	builder.setSpecField(jobImageBuilderType, "imageBuilder", "imageBuilderType")
+
+	return builder
+}
+
+// PullMachineOSConfig pulls existing MachineOSConfig from cluster.
+func PullMachineOSConfig(apiClient *clients.Settings, name string) (*MachineOSConfigBuilder, error) {
+	verbose().Infof("Pulling existing MachineOSConfig name %s from cluster", name)
+
+	builder := MachineOSConfigBuilder{
+		apiClient:  apiClient,
+		Definition: newMachineOSConfigDefinition(name),
+	}
+
+	if name == "" {
+		verbose().Infof("The name of the MachineOSConfig is empty")
+
+		builder.errorMsg = "MachineOSConfig 'name' cannot be empty"
+	}
+
+	exists, err := builder.exists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull MachineOSConfig %s: %w", name, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("MachineOSConfig object %s doesn't exist", name)
+	}
+
+	builder.Definition = builder.Object
+
+	return &builder, nil
+}
+
+// Create makes a MachineOSConfig in cluster and stores the created object in struct.
+func (builder *MachineOSConfigBuilder) Create() (*MachineOSConfigBuilder, error) {
+	if valid, err := builder.validate(); !valid {
+		return builder, err
+	}
+
+	verbose().Infof("Creating the MachineOSConfig %s", builder.Definition.GetName())
+
+	exists, err := builder.exists()
+	if err != nil {
+		return builder, fmt.Errorf("cannot check if MachineOSConfig %s exists: %w", builder.Definition.GetName(), err)
+	}
+
+	if !exists {
+		ctx, cancel := newOperationContext()
+		defer cancel()
+
+		builder.Object, err = builder.apiClient.Resource(machineOSConfigGVR).Create(
+			ctx, builder.Definition, metav1.CreateOptions{})
+	}
+
+	return builder, err
+}
+
+// Delete removes a MachineOSConfig object from a cluster.
+func (builder *MachineOSConfigBuilder) Delete() error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Deleting the MachineOSConfig object %s", builder.Definition.GetName())
+
+	exists, err := builder.exists()
+	if err != nil {
+		return fmt.Errorf("cannot check if MachineOSConfig %s exists: %w", builder.Definition.GetName(), err)
+	}
+
+	if !exists {
+		return fmt.Errorf("MachineOSConfig cannot be deleted because it does not exist")
+	}
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	err = builder.apiClient.Resource(machineOSConfigGVR).Delete(
+		ctx, builder.Definition.GetName(), metav1.DeleteOptions{})
+
+	if err != nil {
+		return fmt.Errorf("cannot delete MachineOSConfig: %w", err)
+	}
+
+	builder.Object = nil
+
+	return nil
+}
+
+// Exists checks whether the given MachineOSConfig exists. A failed request, e.g. a timeout, is reported as not
+// existing.
+func (builder *MachineOSConfigBuilder) Exists() bool {
+	exists, err := builder.exists()
+	if err != nil {
+		verbose().Infof("Failed to check if the MachineOSConfig object exists: %v", err)
+	}
+
+	return exists
+}
+
+// exists fetches the MachineOSConfig object into builder.Object. A missing object is reported without error,
+// any other error of the request is returned and builder.Object is reset.
+func (builder *MachineOSConfigBuilder) exists() (bool, error) {
+	if valid, err := builder.validate(); !valid {
+		return false, err
+	}
+
+	verbose().Infof("Checking if the MachineOSConfig object %s exists", builder.Definition.GetName())
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	object, err := builder.apiClient.Resource(machineOSConfigGVR).Get(
+		ctx, builder.Definition.GetName(), metav1.GetOptions{})
+	if err != nil {
+		builder.Object = nil
+
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get MachineOSConfig %s: %w", builder.Definition.GetName(), err)
+	}
+
+	builder.Object = object
+
+	return true, nil
+}
+
+// WithMachineConfigPoolRef sets the name of the MachineConfigPool the MachineOSConfig builds the layered image for.
+func (builder *MachineOSConfigBuilder) WithMachineConfigPoolRef(poolName string) *MachineOSConfigBuilder {
+	return builder.withRequiredSpecField("machineConfigPool", poolName, "machineConfigPool", "name")
+}
+
+// WithBaseImagePullSecret sets the name of the secret used to pull the base OS image of the layered image.
+func (builder *MachineOSConfigBuilder) WithBaseImagePullSecret(secretName string) *MachineOSConfigBuilder {
+	return builder.withRequiredSpecField("baseImagePullSecret", secretName, "baseImagePullSecret", "name")
+}
+
+// WithRenderedImagePushSecret sets the name of the secret used to push the built layered image.
+func (builder *MachineOSConfigBuilder) WithRenderedImagePushSecret(secretName string) *MachineOSConfigBuilder {
+	return builder.withRequiredSpecField("renderedImagePushSecret", secretName, "renderedImagePushSecret", "name")
+}
+
+// WithRenderedImagePushSpec sets the image pullspec the built layered image is pushed to.
+func (builder *MachineOSConfigBuilder) WithRenderedImagePushSpec(pullSpec string) *MachineOSConfigBuilder {
+	return builder.withRequiredSpecField("renderedImagePushSpec", pullSpec, "renderedImagePushSpec")
+}
+
+// WithContainerfile sets the Containerfile content applied on top of the base OS image for all architectures.
+func (builder *MachineOSConfigBuilder) WithContainerfile(content string) *MachineOSConfigBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting the Containerfile of MachineOSConfig %s", builder.Definition.GetName())
+
+	if content == "" {
+		verbose().Infof("The Containerfile content cannot be empty")
+
+		builder.errorMsg = "'containerfile' content cannot be empty"
+
+		return builder
+	}
+
+	builder.setSpecField([]interface{}{
+		map[string]interface{}{
+			"containerfileArch": noArchContainerfile,
+			"content":           content,
+		},
+	}, "containerFile")
+
+	return builder
+}
+
+// WaitForLayeredBuildSuccess waits for a specific time duration until the latest MachineOSBuild of the
+// MachineOSConfig has succeeded. A failed build is returned immediately along with its failure message.
+func (builder *MachineOSConfigBuilder) WaitForLayeredBuildSuccess(timeout time.Duration) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForLayeredBuildSuccess waits up to specified time %v until the build of MachineOSConfig "+
+		"%s succeeds", timeout, builder.Definition.GetName())
+
+	var lastMessage string
+
+	err := wait.PollImmediate(fiveScds, timeout, func() (bool, error) {
+		machineOSBuild, err := builder.getLatestMachineOSBuild()
+		if err != nil || machineOSBuild == nil {
+			return false, nil
+		}
+
+		conditions, _, _ := unstructured.NestedSlice(machineOSBuild.Object, "status", "conditions")
+
+		for _, condition := range conditions {
+			conditionMap, ok := condition.(map[string]interface{})
+			if !ok || conditionMap["status"] != isTrue {
+				continue
+			}
+
+			switch conditionMap["type"] {
+			case machineOSBuildSucceeded:
+				return true, nil
+			case machineOSBuildFailed:
+				return false, fmt.Errorf("MachineOSBuild %s failed: %v", machineOSBuild.GetName(), conditionMap["message"])
+			default:
+				lastMessage = fmt.Sprintf("%v", conditionMap["message"])
+			}
+		}
+
+		verbose().Infof("MachineOSBuild %s has not succeeded yet: %s", machineOSBuild.GetName(), lastMessage)
+
+		return false, nil
+	})
+
+	if errors.Is(err, wait.ErrWaitTimeout) && lastMessage != "" {
+		return fmt.Errorf("build of MachineOSConfig %s did not succeed within %v, last status: %s: %w",
+			builder.Definition.GetName(), timeout, lastMessage, err)
+	}
+
+	return err
+}
+
+// GetMachineConfigPoolName returns the name of the MachineConfigPool the MachineOSConfig definition targets.
+func (builder *MachineOSConfigBuilder) GetMachineConfigPoolName() (string, error) {
+	if valid, err := builder.validate(); !valid {
+		return "", err
+	}
+
+	poolName, _, err := unstructured.NestedString(builder.Definition.Object, "spec", "machineConfigPool", "name")
+	if err != nil {
+		return "", fmt.Errorf("invalid machineConfigPool of MachineOSConfig %s: %w", builder.Definition.GetName(), err)
+	}
+
+	return poolName, nil
+}
+
+// getLatestMachineOSBuild returns the most recently created MachineOSBuild of the MachineOSConfig, or nil if no
+// build exists yet.
+func (builder *MachineOSConfigBuilder) getLatestMachineOSBuild() (*unstructured.Unstructured, error) {
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	mosbList, err := builder.apiClient.Resource(machineOSBuildGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		verbose().Infof("Failed to list MachineOSBuilds: %v", err)
+
+		return nil, err
+	}
+
+	var latestBuild *unstructured.Unstructured
+
+	for index := range mosbList.Items {
+		machineOSBuild := &mosbList.Items[index]
+
+		moscName, _, _ := unstructured.NestedString(machineOSBuild.Object, "spec", "machineOSConfig", "name")
+		if moscName != builder.Definition.GetName() {
+			continue
+		}
+
+		if latestBuild == nil || latestBuild.GetCreationTimestamp().Time.Before(machineOSBuild.GetCreationTimestamp().Time) {
+			latestBuild = machineOSBuild
+		}
+	}
+
+	return latestBuild, nil
+}
+
+// withRequiredSpecField sets the given non-empty string value at the given path of the MachineOSConfig spec.
+func (builder *MachineOSConfigBuilder) withRequiredSpecField(
+	fieldName, value string, fields ...string) *MachineOSConfigBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting %s of MachineOSConfig %s to %s", fieldName, builder.Definition.GetName(), value)
+
+	if value == "" {
+		verbose().Infof("The %s cannot be empty", fieldName)
+
+		builder.errorMsg = fmt.Sprintf("'%s' cannot be empty", fieldName)
+
+		return builder
+	}
+
+	builder.setSpecField(value, fields...)
+
+	return builder
+}
+
+// setSpecField sets the given value at the given path of the MachineOSConfig spec.
+func (builder *MachineOSConfigBuilder) setSpecField(value interface{}, fields ...string) {
+	err := unstructured.SetNestedField(builder.Definition.Object, value, append([]string{"spec"}, fields...)...)
+	if err != nil {
+		verbose().Infof("Failed to set spec field %v of MachineOSConfig: %v", fields, err)
+
+		builder.errorMsg = fmt.Sprintf("failed to set MachineOSConfig spec field %v: %v", fields, err)
+	}
+}
+
+// newMachineOSConfigDefinition returns an unstructured MachineOSConfig with the given name.
+func newMachineOSConfigDefinition(name string) *unstructured.Unstructured {
+	definition := &unstructured.Unstructured{}
+	definition.SetAPIVersion(machineOSConfigGVR.GroupVersion().String())
+	definition.SetKind(machineOSConfigKind)
+	definition.SetName(name)
+
+	return definition
+}
+
+// validate will check that the builder and builder definition are properly initialized before
+// accessing any member fields.
+func (builder *MachineOSConfigBuilder) validate() (bool, error) {
+	resourceCRD := machineOSConfigKind
+
+	if builder == nil {
+		verbose().Infof("The %s builder is uninitialized", resourceCRD)
+
+		return false, fmt.Errorf("error: received nil %s builder", resourceCRD)
+	}
+
+	if builder.Definition == nil {
+		verbose().Infof("The %s is undefined", resourceCRD)
+
+		builder.errorMsg = msg.UndefinedCrdObjectErrString(resourceCRD)
+	}
+
+	if builder.apiClient == nil {
+		verbose().Infof("The %s builder apiclient is nil", resourceCRD)
+
+		builder.errorMsg = fmt.Sprintf("%s builder cannot have nil apiClient", resourceCRD)
+	}
+
+	if builder.errorMsg != "" {
+		verbose().Infof("The %s builder has error message: %s", resourceCRD, builder.errorMsg)
+
+		return false, fmt.Errorf(builder.errorMsg)
+	}
+
+	return true, nil
+}
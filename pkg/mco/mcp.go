@@ -2,26 +2,77 @@ package mco
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/golang/glog"
+	"github.com/google/go-cmp/cmp"
 	"github.com/openshift-kni/eco-goinfra/pkg/clients"
 	"github.com/openshift-kni/eco-goinfra/pkg/msg"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 
+	configv1 "github.com/openshift/api/config/v1"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	daemonconsts "github.com/openshift/machine-config-operator/pkg/daemon/constants"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/yaml"
 )
 
 const (
-	fiveScds          time.Duration = 5 * time.Second
-	isTrue                          = "True"
-	machineConfigPool               = "MachineConfigPool"
+	fiveScds                  time.Duration = 5 * time.Second
+	isTrue                                  = "True"
+	machineConfigPool                       = "MachineConfigPool"
+	machineConfigKind                       = "MachineConfig"
+	mcoNamespace                            = "openshift-machine-config-operator"
+	mcdLabelSelector                        = "k8s-app=machine-config-daemon"
+	mcdContainerName                        = "machine-config-daemon"
+	mcdHostRoot                             = "/rootfs"
+	mcdLogsSince              time.Duration = time.Hour
+	evictionTimeout           time.Duration = 5 * time.Minute
+	mirrorPodAnnotation                     = "kubernetes.io/config.mirror"
+	nodeRoleLabelPrefix                     = "node-role.kubernetes.io/"
+	masterPoolName                          = "master"
+	workerPoolName                          = "worker"
+	layeringEnabledPoolLabel                = "machineconfiguration.openshift.io/layering-enabled"
+	nodeSelectorSettleTimeout time.Duration = 2 * time.Minute
+	clusterFeatureGateName                  = "cluster"
+	builtInPoolLabel                        = "machineconfiguration.openshift.io/mco-built-in"
+	poolOwnershipLabelPrefix                = "pools.operator.machineconfiguration.openshift.io/"
 )
 
+// operationTimeout bounds every API call of a builder without a context set with WithContext.
+var operationTimeout = 30 * time.Second
+
+// webhookRetryBackoff is the backoff of the MachineConfigPool creation retries on transient admission errors.
+var webhookRetryBackoff = wait.Backoff{Steps: 4, Duration: 2 * time.Second, Factor: 2, Jitter: 0.1}
+
+// versionGatedSpecFields maps the MachineConfigPool spec fields that were added after OpenShift 4.0 to the first
+// OpenShift 4.y minor version accepting them.
+var versionGatedSpecFields = map[string]int{
+	"pinnedImageSets": 16,
+}
+
 // MCPBuilder provides struct for MachineConfigPool object which contains connection to cluster
 // and MachineConfigPool definitions.
 type MCPBuilder struct {
@@ -33,6 +84,97 @@ type MCPBuilder struct {
 	apiClient *clients.Settings
 	// errorMsg is processed before MachineConfigPool object is created.
 	errorMsg string
+	// pollInterval is the interval used by the wait methods, defaults to five seconds when unset.
+	pollInterval time.Duration
+	// defaultTimeout is the timeout used by the wait methods that take no explicit timeout.
+	defaultTimeout time.Duration
+	// allowReservedName allows to create a MachineConfigPool named like a built-in pool.
+	allowReservedName bool
+	// skipExistsCheck makes Create attempt the creation without checking if the object exists first.
+	skipExistsCheck bool
+	// emptyMcSelector marks the machineConfigSelector as intentionally empty.
+	emptyMcSelector bool
+	// unstructuredSpec is the spec of the unstructured object the builder was created from. It keeps the fields
+	// the vendored MachineConfigPool type does not know, e.g. pinnedImageSets.
+	unstructuredSpec map[string]interface{}
+	// ctx is the parent context of the API calls made by Create, Update, Delete and Exists. When unset every
+	// call is bounded by operationTimeout.
+	ctx context.Context
+	// observer is invoked on completion of every Create, Update, Delete and Wait operation.
+	observer MCPObserver
+	// conversionScheme is used by ToYAML and ToUnstructured to convert the definition to the API version known
+	// by the target cluster. When unset the definition is encoded as machineconfiguration.openshift.io/v1.
+	conversionScheme *runtime.Scheme
+}
+
+// MCPObserver is invoked with the operation name, its duration and its resulting error on completion of
+// MachineConfigPool builder operations.
+type MCPObserver func(operation string, duration time.Duration, err error)
+
+// PoolSynchronizerStatus describes the rollout of one synchronizer of a MachineConfigPool, as reported in the
+// status.poolSynchronizersStatus field of newer MCO versions. The vendored MCO API predates the field.
+type PoolSynchronizerStatus struct {
+	PoolSynchronizerType    string `json:"poolSynchronizerType"`
+	MachineCount            int64  `json:"machineCount"`
+	UpdatedMachineCount     int64  `json:"updatedMachineCount"`
+	ReadyMachineCount       int64  `json:"readyMachineCount"`
+	AvailableMachineCount   int64  `json:"availableMachineCount"`
+	UnavailableMachineCount int64  `json:"unavailableMachineCount"`
+	ObservedGeneration      int64  `json:"observedGeneration,omitempty"`
+}
+
+// NodeConfigState describes the MachineConfig rollout state of a single node as reported by the
+// machine-config-daemon node annotations.
+type NodeConfigState struct {
+	// DesiredConfig is the rendered MachineConfig the node should run.
+	DesiredConfig string
+	// CurrentConfig is the rendered MachineConfig the node is currently running.
+	CurrentConfig string
+	// State is the machine-config-daemon state of the node, e.g. Done, Working or Degraded.
+	State string
+}
+
+// NodeDrift describes whether a node of a MachineConfigPool is still behind its desired rendered MachineConfig.
+type NodeDrift struct {
+	// NodeName is the name of the node.
+	NodeName string
+	// CurrentConfig is the rendered MachineConfig the node is currently running.
+	CurrentConfig string
+	// DesiredConfig is the rendered MachineConfig the node should run.
+	DesiredConfig string
+	// Drifted is true when the current config of the node differs from its desired config.
+	Drifted bool
+}
+
+// MCPDiagnosis is a troubleshooting snapshot of a MachineConfigPool.
+type MCPDiagnosis struct {
+	// Conditions are the current conditions of the MachineConfigPool.
+	Conditions []mcov1.MachineConfigPoolCondition
+	// MachineCount is the total number of machines in the MachineConfigPool.
+	MachineCount int32
+	// UpdatedMachineCount is the number of machines running the current config of the MachineConfigPool.
+	UpdatedMachineCount int32
+	// ReadyMachineCount is the number of ready machines in the MachineConfigPool.
+	ReadyMachineCount int32
+	// UnavailableMachineCount is the number of unavailable machines in the MachineConfigPool.
+	UnavailableMachineCount int32
+	// DegradedMachineCount is the number of degraded machines in the MachineConfigPool.
+	DegradedMachineCount int32
+	// Generation is the generation of the MachineConfigPool spec.
+	Generation int64
+	// ObservedGeneration is the generation last observed by the MachineConfigPool controller.
+	ObservedGeneration int64
+	// DegradedNodes are the names of the nodes whose machine-config-daemon state is Degraded or Unreconcilable.
+	DegradedNodes []string
+	// Events are the events recorded for the MachineConfigPool.
+	Events []corev1.Event
+}
+
+// jsonPatchOperation is a single JSON patch operation applied to the MachineConfigPool object.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
 }
 
 // MCPAdditionalOptions additional options for mcp object.
@@ -40,7 +182,7 @@ type MCPAdditionalOptions func(builder *MCPBuilder) (*MCPBuilder, error)
 
 // NewMCPBuilder method creates new instance of builder.
 func NewMCPBuilder(apiClient *clients.Settings, mcpName string) *MCPBuilder {
-	glog.V(100).Infof(
+	verbose().Infof(
 		"Initializing new MCPBuilder structure with the following params: %s", mcpName)
 
 	builder := &MCPBuilder{
@@ -53,17 +195,22 @@ func NewMCPBuilder(apiClient *clients.Settings, mcpName string) *MCPBuilder {
 	}
 
 	if mcpName == "" {
-		glog.V(100).Infof("The name of the MachineConfigPool is empty")
+		verbose().Infof("The name of the MachineConfigPool is empty")
 
 		builder.errorMsg = "MachineConfigPool 'name' cannot be empty"
 	}
 
+	if isReservedPoolName(mcpName) {
+		verbose().Infof("The MachineConfigPool name %s is reserved for a built-in pool, Create is rejected "+
+			"unless AllowReservedName is used", mcpName)
+	}
+
 	return builder
 }
 
 // Pull pulls existing machineconfigpool from cluster.
 func Pull(apiClient *clients.Settings, name string) (*MCPBuilder, error) {
-	glog.V(100).Infof("Pulling existing machineconfigpool name %s from cluster", name)
+	verbose().Infof("Pulling existing machineconfigpool name %s from cluster", name)
 
 	builder := MCPBuilder{
 		apiClient: apiClient,
@@ -72,15 +219,22 @@ func Pull(apiClient *clients.Settings, name string) (*MCPBuilder, error) {
 				Name: name,
 			},
 		},
+		// pulled pools already exist on the cluster, including the built-in ones.
+		allowReservedName: true,
 	}
 
 	if name == "" {
-		glog.V(100).Infof("The name of the machineconfigpool is empty")
+		verbose().Infof("The name of the machineconfigpool is empty")
 
 		builder.errorMsg = "machineconfigpool 'name' cannot be empty"
 	}
 
-	if !builder.Exists() {
+	exists, err := builder.exists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull machineconfigpool %s: %w", name, err)
+	}
+
+	if !exists {
 		return nil, fmt.Errorf("machineconfigpool object %s doesn't exist", name)
 	}
 
@@ -89,302 +243,3857 @@ func Pull(apiClient *clients.Settings, name string) (*MCPBuilder, error) {
 	return &builder, nil
 }
 
-// Create makes a MachineConfigPool in cluster and stores the created object in struct.
-func (builder *MCPBuilder) Create() (*MCPBuilder, error) {
-	if valid, err := builder.validate(); !valid {
-		return builder, err
-	}
+// PullStrict pulls existing machineconfigpool from cluster. Unlike Pull, the actual API error is returned,
+// so that callers can distinguish a missing machineconfigpool from e.g. a forbidden or failed request.
+func PullStrict(apiClient *clients.Settings, name string) (*MCPBuilder, error) {
+	verbose().Infof("Strictly pulling existing machineconfigpool name %s from cluster", name)
 
-	glog.V(100).Infof("Creating the MachineConfigPool %s",
-		builder.Definition.Name)
+	if apiClient == nil {
+		verbose().Infof("The apiClient of the machineconfigpool is empty")
 
-	var err error
-	if !builder.Exists() {
-		builder.Object, err = builder.apiClient.MachineConfigPools().Create(
-			context.TODO(), builder.Definition, metav1.CreateOptions{})
+		return nil, fmt.Errorf("machineconfigpool 'apiClient' cannot be empty")
 	}
 
-	return builder, err
-}
+	if name == "" {
+		verbose().Infof("The name of the machineconfigpool is empty")
 
-// Delete removes a MachineConfigPool object from a cluster.
-func (builder *MCPBuilder) Delete() error {
-	if valid, err := builder.validate(); !valid {
-		return err
+		return nil, fmt.Errorf("machineconfigpool 'name' cannot be empty")
 	}
 
-	glog.V(100).Infof("Deleting the MachineConfigPool object %s",
-		builder.Definition.Name)
+	ctx, cancel := newOperationContext()
+	defer cancel()
 
-	if !builder.Exists() {
-		return fmt.Errorf("MachineConfigPool cannot be deleted because it does not exist")
+	mcp, err := apiClient.MachineConfigPools().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		verbose().Infof("Failed to get machineconfigpool %s: %v", name, err)
+
+		return nil, fmt.Errorf("failed to get machineconfigpool %s: %w", name, err)
 	}
 
-	err := builder.apiClient.MachineConfigPools().Delete(
-		context.TODO(), builder.Object.Name, metav1.DeleteOptions{})
+	return &MCPBuilder{
+		apiClient:         apiClient,
+		Definition:        mcp,
+		Object:            mcp,
+		allowReservedName: true,
+	}, nil
+}
 
-	if err != nil {
-		return fmt.Errorf("cannot delete MachineConfigPool: %w", err)
+// NewMCPBuilderFromExisting creates a new instance of builder whose definition has the given name and a copy of
+// the spec of the source MachineConfigPool, e.g. to replicate a production pool into a test pool. The metadata and
+// status of the source are not copied.
+func NewMCPBuilderFromExisting(
+	apiClient *clients.Settings, source *mcov1.MachineConfigPool, newName string) *MCPBuilder {
+	verbose().Infof("Initializing new MCPBuilder structure %s from an existing MachineConfigPool", newName)
+
+	builder := NewMCPBuilder(apiClient, newName)
+
+	if source == nil {
+		verbose().Infof("The source MachineConfigPool is nil")
+
+		builder.errorMsg = "source MachineConfigPool cannot be nil"
+
+		return builder
 	}
 
-	return err
+	builder.Definition.Spec = *source.Spec.DeepCopy()
+	// the rendered config is set by the controller for the source pool and does not apply to the new pool.
+	builder.Definition.Spec.Configuration = mcov1.MachineConfigPoolStatusConfiguration{}
+
+	return builder
 }
 
-// Exists checks whether the given MachineConfigPool exists.
-func (builder *MCPBuilder) Exists() bool {
-	if valid, _ := builder.validate(); !valid {
-		return false
-	}
+// NewMCPBuilderFromUnstructured creates a new instance of builder whose definition is converted from the given
+// unstructured MachineConfigPool, e.g. one obtained through the dynamic client.
+func NewMCPBuilderFromUnstructured(apiClient *clients.Settings, object *unstructured.Unstructured) *MCPBuilder {
+	verbose().Infof("Initializing new MCPBuilder structure from an unstructured object")
 
-	glog.V(100).Infof("Checking if the MachineConfigPool object %s exists",
-		builder.Definition.Name)
+	builder := &MCPBuilder{
+		apiClient:  apiClient,
+		Definition: &mcov1.MachineConfigPool{},
+	}
 
-	var err error
-	builder.Object, err = builder.apiClient.MachineConfigPools().Get(
-		context.Background(), builder.Definition.Name, metav1.GetOptions{})
+	if object == nil {
+		verbose().Infof("The unstructured MachineConfigPool is nil")
 
-	return err == nil || !k8serrors.IsNotFound(err)
-}
+		builder.errorMsg = "unstructured MachineConfigPool cannot be nil"
 
-// WithMcSelector defines the machineConfigSelector in the machine config pool.
-func (builder *MCPBuilder) WithMcSelector(mcSelector map[string]string) *MCPBuilder {
-	if valid, _ := builder.validate(); !valid {
 		return builder
 	}
 
-	glog.V(100).Infof("WithMcSelector updates builder object with "+
-		"machineConfigSelector label: %v", mcSelector)
+	if kind := object.GetKind(); kind != "" && kind != machineConfigPool {
+		verbose().Infof("The unstructured object has kind %s instead of %s", kind, machineConfigPool)
 
-	if len(mcSelector) == 0 {
-		builder.errorMsg = "'machineConfigSelector MatchLabels' field cannot be empty"
+		builder.errorMsg = fmt.Sprintf("unstructured object has kind %s, expected %s", kind, machineConfigPool)
+
+		return builder
 	}
 
-	if builder.errorMsg != "" {
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, builder.Definition)
+	if err != nil {
+		verbose().Infof("Failed to convert the unstructured MachineConfigPool: %v", err)
+
+		builder.errorMsg = fmt.Sprintf("failed to convert unstructured MachineConfigPool: %v", err)
+
 		return builder
 	}
 
-	builder.Definition.Spec.MachineConfigSelector.MatchLabels = mcSelector
+	if builder.Definition.Name == "" {
+		verbose().Infof("The name of the MachineConfigPool is empty")
+
+		builder.errorMsg = "MachineConfigPool 'name' cannot be empty"
+	}
+
+	builder.unstructuredSpec, _, _ = unstructured.NestedMap(object.Object, "spec")
 
 	return builder
 }
 
-// WaitToBeInCondition waits for a specific time duration until the MachineConfigPool will have a
-// specified condition type with the expected status.
-func (builder *MCPBuilder) WaitToBeInCondition(
-	conditionType mcov1.MachineConfigPoolConditionType,
-	conditionStatus corev1.ConditionStatus,
-	timeout time.Duration,
-) error {
+// Create makes a MachineConfigPool in cluster and stores the created object in struct. If the MachineConfigPool
+// already exists, the existing object is stored instead, so after a successful Create the Object always reflects
+// the cluster state. Changes made to the Definition after Create are applied with Update.
+func (builder *MCPBuilder) Create() (_ *MCPBuilder, err error) {
+	defer builder.observe("Create", time.Now(), &err)
+
 	if valid, err := builder.validate(); !valid {
-		return err
+		return builder, err
 	}
 
-	glog.V(100).Infof("WaitToBeInCondition waits up to specified time duration %v until "+
-		"MachineConfigPool condition %v is met", timeout, conditionType)
+	verbose().Infof("Creating the MachineConfigPool %s",
+		builder.Definition.Name)
 
-	return wait.PollImmediate(fiveScds, timeout, func() (bool, error) {
-		mcp, err := builder.apiClient.MachineConfigPools().Get(context.Background(),
-			builder.Object.Name, metav1.GetOptions{})
+	if isReservedPoolName(builder.Definition.Name) && !builder.allowReservedName {
+		verbose().Infof("The MachineConfigPool name %s is reserved for a built-in pool", builder.Definition.Name)
 
-		if err != nil {
-			return false, nil
+		return builder, fmt.Errorf("MachineConfigPool name %s is reserved for a built-in pool, "+
+			"use AllowReservedName to create it anyway", builder.Definition.Name)
+	}
+
+	if !builder.skipExistsCheck {
+		// exists refreshes builder.Object with the existing object.
+		exists, existsErr := builder.exists()
+		if existsErr != nil {
+			return builder, fmt.Errorf("cannot check if MachineConfigPool %s exists: %w",
+				builder.Definition.Name, existsErr)
 		}
 
-		for _, condition := range mcp.Status.Conditions {
-			if condition.Type == conditionType && condition.Status == conditionStatus {
-				return true, nil
-			}
+		if exists {
+			verbose().Infof("The MachineConfigPool %s already exists", builder.Definition.Name)
+
+			return builder, nil
 		}
+	}
 
-		return false, nil
+	// slow admission webhooks make the creation time out transiently, so it is retried with backoff.
+	err = retry.OnError(webhookRetryBackoff, isTransientAdmissionError, func() error {
+		ctx, cancel := builder.operationContext()
+		defer cancel()
+
+		var createErr error
+
+		builder.Object, createErr = builder.apiClient.MachineConfigPools().Create(
+			ctx, builder.Definition, metav1.CreateOptions{})
+		if isTransientAdmissionError(createErr) {
+			verbose().Infof("Creating the MachineConfigPool %s failed transiently, retrying: %v",
+				builder.Definition.Name, createErr)
+		}
+
+		return createErr
 	})
+
+	// a timed out creation may have succeeded, so an already existing object is fetched.
+	if k8serrors.IsAlreadyExists(err) {
+		verbose().Infof("The MachineConfigPool %s already exists", builder.Definition.Name)
+
+		ctx, cancel := builder.operationContext()
+		defer cancel()
+
+		builder.Object, err = builder.apiClient.MachineConfigPools().Get(ctx, builder.Definition.Name, metav1.GetOptions{})
+	}
+
+	if err != nil {
+		verbose().Infof("Failed to create the MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+		builder.Object = nil
+	}
+
+	return builder, err
 }
 
-// WaitForUpdate waits for a MachineConfigPool to be updating and then updated.
-func (builder *MCPBuilder) WaitForUpdate(timeout time.Duration) error {
+// Update renovates the existing MachineConfigPool object with the MachineConfigPool definition in builder.
+// Without a resourceVersion in the definition, see WithResourceVersion, the last writer wins.
+func (builder *MCPBuilder) Update() (_ *MCPBuilder, err error) {
+	defer builder.observe("Update", time.Now(), &err)
+
 	if valid, err := builder.validate(); !valid {
-		return err
+		return builder, err
 	}
 
-	glog.V(100).Infof("WaitForUpdate waits up to specified time %v until updating"+
-		" machineConfigPool object is updated", timeout)
+	verbose().Infof("Updating the MachineConfigPool %s", builder.Definition.Name)
 
-	mcpUpdating, err := builder.apiClient.MachineConfigPools().Get(context.Background(),
-		builder.Object.Name, metav1.GetOptions{})
+	ctx, cancel := builder.operationContext()
+	defer cancel()
 
-	if err != nil {
-		return err
+	builder.Object, err = builder.apiClient.MachineConfigPools().Update(ctx, builder.Definition, metav1.UpdateOptions{})
+
+	return builder, err
+}
+
+// CreateOrUpdate makes a MachineConfigPool in cluster if it does not exist yet, otherwise updates the existing
+// MachineConfigPool object with the MachineConfigPool definition in builder. Update conflicts are retried with the
+// latest resourceVersion, unless a resourceVersion was set with WithResourceVersion, in which case a conflict is
+// returned.
+func (builder *MCPBuilder) CreateOrUpdate() (_ *MCPBuilder, err error) {
+	defer builder.observe("CreateOrUpdate", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return builder, err
 	}
 
-	for _, condition := range mcpUpdating.Status.Conditions {
-		if condition.Type == "Updating" && condition.Status == isTrue {
-			err := wait.PollImmediate(fiveScds, timeout, func() (bool, error) {
-				mcpUpdated, err := builder.apiClient.MachineConfigPools().Get(context.Background(),
-					builder.Object.Name, metav1.GetOptions{})
+	verbose().Infof("Creating or updating the MachineConfigPool %s", builder.Definition.Name)
 
-				if err != nil {
-					return false, nil
-				}
+	exists, err := builder.exists()
+	if err != nil {
+		return builder, fmt.Errorf("cannot check if MachineConfigPool %s exists: %w", builder.Definition.Name, err)
+	}
 
-				for _, condition := range mcpUpdated.Status.Conditions {
-					if condition.Type == "Updated" && condition.Status == isTrue {
-						return true, nil
-					}
-				}
+	if !exists {
+		return builder.Create()
+	}
 
-				return false, nil
-			})
+	if builder.Definition.ResourceVersion != "" {
+		verbose().Infof("Updating the MachineConfigPool %s at resourceVersion %s",
+			builder.Definition.Name, builder.Definition.ResourceVersion)
 
+		_, err = builder.Update()
+	} else {
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			exists, err := builder.exists()
 			if err != nil {
 				return err
 			}
-		}
+
+			if !exists {
+				return fmt.Errorf("MachineConfigPool %s cannot be updated because it does not exist",
+					builder.Definition.Name)
+			}
+
+			builder.Definition.ResourceVersion = builder.Object.ResourceVersion
+
+			_, err = builder.Update()
+
+			return err
+		})
+
+		// the live resourceVersion must not be mistaken for one set by the caller on the next call.
+		builder.Definition.ResourceVersion = ""
 	}
 
-	return nil
+	if err != nil {
+		return builder, fmt.Errorf("cannot update MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	return builder, nil
 }
 
-// WaitToBeStableFor waits on MachineConfigPool to stable for a time duration or until timeout.
-func (builder *MCPBuilder) WaitToBeStableFor(stableDuration time.Duration, timeout time.Duration) error {
+// Delete removes a MachineConfigPool object from a cluster.
+func (builder *MCPBuilder) Delete() (err error) {
+	defer builder.observe("Delete", time.Now(), &err)
+
 	if valid, err := builder.validate(); !valid {
 		return err
 	}
 
-	glog.V(100).Infof("WaitToBeStableFor waits up to duration of %v for "+
-		"MachineConfigPool to be stable for %v", timeout, stableDuration)
+	verbose().Infof("Deleting the MachineConfigPool object %s",
+		builder.Definition.Name)
 
-	isMcpStable := true
+	exists, err := builder.exists()
+	if err != nil {
+		return fmt.Errorf("cannot check if MachineConfigPool %s exists: %w", builder.Definition.Name, err)
+	}
 
-	// Wait 5 secs in each iteration before condition function () returns true or errors
-	// or times out after stableDuration
-	err := wait.PollImmediate(fiveScds, timeout, func() (bool, error) {
+	if !exists {
+		return fmt.Errorf("MachineConfigPool cannot be deleted because it does not exist")
+	}
 
-		isMcpStable = true
+	ctx, cancel := builder.operationContext()
+	defer cancel()
 
-		_ = wait.PollImmediate(fiveScds, stableDuration, func() (done bool, err error) {
+	err = builder.apiClient.MachineConfigPools().Delete(ctx, builder.Definition.Name, metav1.DeleteOptions{})
 
-			if !builder.Exists() {
-				return false, nil
-			}
+	if err != nil {
+		return fmt.Errorf("cannot delete MachineConfigPool: %w", err)
+	}
 
-			if builder.Object.Status.ReadyMachineCount != builder.Object.Status.MachineCount ||
-				builder.Object.Status.MachineCount != builder.Object.Status.UpdatedMachineCount ||
-				builder.Object.Status.DegradedMachineCount != 0 {
+	return err
+}
 
-				glog.V(100).Infof("MachineConfigPool: %v degraded and has a mismatch in "+
-					"machineCount: %v "+"vs machineCountUpdated: "+"%v vs readyMachineCount: %v and "+
-					"degradedMachineCount is : %v \n", builder.Object.ObjectMeta.Name,
-					builder.Object.Status.MachineCount, builder.Object.Status.UpdatedMachineCount,
-					builder.Object.Status.ReadyMachineCount, builder.Object.Status.DegradedMachineCount)
+// Exists checks whether the given MachineConfigPool exists. A failed request, e.g. a timeout, is reported as not
+// existing.
+func (builder *MCPBuilder) Exists() bool {
+	exists, err := builder.exists()
+	if err != nil {
+		verbose().Infof("Failed to check if the MachineConfigPool object exists: %v", err)
+	}
 
-				isMcpStable = false
+	return exists
+}
 
-				return true, nil
-			}
+// exists fetches the MachineConfigPool object into builder.Object. A missing object is reported without error,
+// any other error of the request is returned and builder.Object is reset.
+func (builder *MCPBuilder) exists() (bool, error) {
+	if valid, err := builder.validate(); !valid {
+		return false, err
+	}
 
-			return false, nil
-		})
+	verbose().Infof("Checking if the MachineConfigPool object %s exists",
+		builder.Definition.Name)
 
-		if isMcpStable {
-			glog.V(100).Infof("MachineConfigPool was stable during during stableDuration: %v",
-				stableDuration)
+	ctx, cancel := builder.operationContext()
+	defer cancel()
 
-			// this will exit the outer wait.PollImmediate block since the mcp was stable during stableDuration
-			return true, nil
+	mcp, err := builder.apiClient.MachineConfigPools().Get(ctx, builder.Definition.Name, metav1.GetOptions{})
+	if err != nil {
+		builder.Object = nil
+
+		if k8serrors.IsNotFound(err) {
+			return false, nil
 		}
 
-		glog.V(100).Infof("MachineConfigPool was not stable during stableDuration: %v, retrying ...",
-			stableDuration)
+		return false, fmt.Errorf("failed to get MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
 
-		// keep iterating in the outer wait.PollImmediate waiting for cluster to be stable
-		return false, nil
-	})
+	builder.Object = mcp
 
-	// After the timout in outer wait.PollImmediate.
-	if err == nil {
-		glog.V(100).Infof("Cluster was stable during stableDuration: %v", stableDuration)
-	} else {
-		// Here err is "timed out waiting for the condition"
-		glog.V(100).Infof("Cluster was Un-stable during stableDuration: %v", stableDuration)
+	return true, nil
+}
+
+// Equals returns true if both builders refer to the MachineConfigPool with the same name.
+func (builder *MCPBuilder) Equals(other *MCPBuilder) bool {
+	if builder == nil || other == nil || builder.Definition == nil || other.Definition == nil {
+		return false
 	}
 
-	return err
+	return builder.Definition.Name == other.Definition.Name
 }
 
-// WithOptions creates mcp with generic mutation options.
-func (builder *MCPBuilder) WithOptions(options ...MCPAdditionalOptions) *MCPBuilder {
+// IsCustomPool returns true if the MachineConfigPool is not one of the built-in master and worker pools. A pool is
+// built-in when it has a built-in name, carries the mco-built-in label or carries the
+// pools.operator.machineconfiguration.openshift.io ownership label of its own name, as the operator sets on the
+// pools it owns. A custom pool inheriting from a built-in pool, i.e. carrying the ownership label of that pool only,
+// is still custom.
+func (builder *MCPBuilder) IsCustomPool() bool {
 	if valid, _ := builder.validate(); !valid {
-		return builder
+		return false
 	}
 
-	glog.V(100).Infof("Setting mcp additional options")
+	verbose().Infof("Checking if MachineConfigPool %s is a custom pool", builder.Definition.Name)
 
-	for _, option := range options {
-		if option != nil {
-			builder, err := option(builder)
+	if isReservedPoolName(builder.Definition.Name) {
+		return false
+	}
 
-			if err != nil {
-				glog.V(100).Infof("Error occurred in mutation function")
+	poolLabels := builder.Definition.Labels
+	if builder.Object != nil {
+		poolLabels = builder.Object.Labels
+	}
 
-				builder.errorMsg = err.Error()
+	if _, found := poolLabels[builtInPoolLabel]; found {
+		return false
+	}
 
-				return builder
-			}
+	for label := range poolLabels {
+		role := strings.TrimPrefix(label, poolOwnershipLabelPrefix)
+		if role == label {
+			continue
 		}
+
+		if role == builder.Definition.Name {
+			verbose().Infof("MachineConfigPool %s is owned by the operator", builder.Definition.Name)
+
+			return false
+		}
+
+		verbose().Infof("MachineConfigPool %s is a custom pool inheriting from the %s pool",
+			builder.Definition.Name, role)
 	}
 
-	return builder
+	return true
 }
 
-// IsInCondition parses MachineConfigPool conditions.
-// Returns true if given MachineConfigPool is in given condition, otherwise false.
-func (builder *MCPBuilder) IsInCondition(mcpConditionType mcov1.MachineConfigPoolConditionType) bool {
+// WithMcSelector defines the machineConfigSelector in the machine config pool.
+func (builder *MCPBuilder) WithMcSelector(mcSelector map[string]string) *MCPBuilder {
 	if valid, _ := builder.validate(); !valid {
-		return false
+		return builder
 	}
 
-	glog.V(100).Infof("IsInCondition returns true"+
-		" if MachineConfigPool object is in a given condition %v, otherwise false", mcpConditionType)
+	verbose().Infof("WithMcSelector updates builder object with "+
+		"machineConfigSelector label: %v", mcSelector)
 
-	if builder.Exists() {
-		for _, condition := range builder.Object.Status.Conditions {
-			if condition.Type == mcpConditionType && condition.Status == isTrue {
-				return true
-			}
-		}
+	if len(mcSelector) == 0 {
+		builder.errorMsg = "'machineConfigSelector MatchLabels' field cannot be empty"
 	}
 
-	return false
-}
+	if builder.errorMsg != "" {
+		return builder
+	}
 
-// validate will check that the builder and builder definition are properly initialized before
-// accessing any member fields.
-func (builder *MCPBuilder) validate() (bool, error) {
-	resourceCRD := "MachineConfigPool"
+	if builder.Definition.Spec.MachineConfigSelector == nil {
+		builder.Definition.Spec.MachineConfigSelector = &metav1.LabelSelector{}
+	}
 
-	if builder == nil {
-		glog.V(100).Infof("The %s builder is uninitialized", resourceCRD)
+	builder.Definition.Spec.MachineConfigSelector.MatchLabels = mcSelector
 
-		return false, fmt.Errorf("error: received nil %s builder", resourceCRD)
+	return builder
+}
+
+// WithMcSelectorFull defines the machineConfigSelector in the machine config pool with the given label selector,
+// supporting both matchLabels and matchExpressions.
+func (builder *MCPBuilder) WithMcSelectorFull(selector metav1.LabelSelector) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
 	}
 
-	if builder.Definition == nil {
-		glog.V(100).Infof("The %s is undefined", resourceCRD)
+	verbose().Infof("WithMcSelectorFull updates builder object with machineConfigSelector: %v", selector)
 
-		builder.errorMsg = msg.UndefinedCrdObjectErrString(resourceCRD)
-	}
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		verbose().Infof("The machineConfigSelector cannot be empty")
 
-	if builder.apiClient == nil {
-		glog.V(100).Infof("The %s builder apiclient is nil", resourceCRD)
+		builder.errorMsg = "'machineConfigSelector' must have matchLabels or matchExpressions"
 
-		builder.errorMsg = fmt.Sprintf("%s builder cannot have nil apiClient", resourceCRD)
+		return builder
 	}
 
-	if builder.errorMsg != "" {
-		glog.V(100).Infof("The %s builder has error message: %s", resourceCRD, builder.errorMsg)
+	if _, err := metav1.LabelSelectorAsSelector(&selector); err != nil {
+		verbose().Infof("The machineConfigSelector is invalid: %v", err)
 
-		return false, fmt.Errorf(builder.errorMsg)
-	}
+		builder.errorMsg = fmt.Sprintf("'machineConfigSelector' is invalid: %v", err)
+
+		return builder
+	}
+
+	builder.Definition.Spec.MachineConfigSelector = selector.DeepCopy()
+
+	return builder
+}
+
+// WithEmptyMcSelector explicitly defines an empty, but not nil, machineConfigSelector in the machine config pool.
+// Note that following the label selector semantics an empty selector matches every MachineConfig.
+func (builder *MCPBuilder) WithEmptyMcSelector() *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("WithEmptyMcSelector updates builder object with an empty machineConfigSelector")
+
+	builder.Definition.Spec.MachineConfigSelector = &metav1.LabelSelector{}
+	builder.emptyMcSelector = true
+
+	return builder
+}
+
+// WithNodeSelectorFull defines the nodeSelector in the machine config pool with the given label selector,
+// supporting both matchLabels and matchExpressions.
+func (builder *MCPBuilder) WithNodeSelectorFull(selector metav1.LabelSelector) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("WithNodeSelectorFull updates builder object with nodeSelector: %v", selector)
+
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		verbose().Infof("The nodeSelector cannot be empty")
+
+		builder.errorMsg = "'nodeSelector' must have matchLabels or matchExpressions"
+
+		return builder
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(&selector); err != nil {
+		verbose().Infof("The nodeSelector is invalid: %v", err)
+
+		builder.errorMsg = fmt.Sprintf("'nodeSelector' is invalid: %v", err)
+
+		return builder
+	}
+
+	builder.Definition.Spec.NodeSelector = selector.DeepCopy()
+
+	return builder
+}
+
+// WithConfigurationSource defines the MachineConfigs used to render the configuration of the machine config pool.
+func (builder *MCPBuilder) WithConfigurationSource(sources []corev1.ObjectReference) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting MachineConfigPool %s configuration source to %v", builder.Definition.Name, sources)
+
+	if len(sources) == 0 {
+		verbose().Infof("The configuration source cannot be empty")
+
+		builder.errorMsg = "'configuration source' cannot be empty"
+
+		return builder
+	}
+
+	for _, source := range sources {
+		if source.Name == "" {
+			verbose().Infof("The configuration source name cannot be empty")
+
+			builder.errorMsg = "'configuration source' name cannot be empty"
+
+			return builder
+		}
+
+		if source.Kind != machineConfigKind {
+			verbose().Infof("The configuration source %s has kind %s", source.Name, source.Kind)
+
+			builder.errorMsg = fmt.Sprintf("'configuration source' %s must have kind %s, got %q",
+				source.Name, machineConfigKind, source.Kind)
+
+			return builder
+		}
+	}
+
+	builder.Definition.Spec.Configuration.Source = append([]corev1.ObjectReference{}, sources...)
+
+	return builder
+}
+
+// WithSpec sets the whole spec of the MachineConfigPool definition. The machineConfigSelector is mandatory.
+func (builder *MCPBuilder) WithSpec(spec mcov1.MachineConfigPoolSpec) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting MachineConfigPool %s spec to %v", builder.Definition.Name, spec)
+
+	if spec.MachineConfigSelector == nil {
+		verbose().Infof("The machineConfigSelector of the spec cannot be nil")
+
+		builder.errorMsg = "'machineConfigSelector' of the spec cannot be nil"
+
+		return builder
+	}
+
+	builder.Definition.Spec = *spec.DeepCopy()
+
+	return builder
+}
+
+// WithResourceVersion sets the resourceVersion of the MachineConfigPool definition, so that Update fails with
+// a conflict if the MachineConfigPool object was changed in the meantime. Without it Update overwrites the object.
+func (builder *MCPBuilder) WithResourceVersion(resourceVersion string) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting MachineConfigPool %s resourceVersion to %s", builder.Definition.Name, resourceVersion)
+
+	if resourceVersion == "" {
+		verbose().Infof("The resourceVersion cannot be empty")
+
+		builder.errorMsg = "'resourceVersion' cannot be empty"
+
+		return builder
+	}
+
+	builder.Definition.ResourceVersion = resourceVersion
+
+	return builder
+}
+
+// WithFinalizer appends the given finalizer to the MachineConfigPool definition. The finalizer must be
+// domain-prefixed, e.g. example.com/cleanup.
+func (builder *MCPBuilder) WithFinalizer(finalizer string) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Adding finalizer %s to MachineConfigPool %s", finalizer, builder.Definition.Name)
+
+	if finalizer == "" {
+		verbose().Infof("The finalizer cannot be empty")
+
+		builder.errorMsg = "'finalizer' cannot be empty"
+
+		return builder
+	}
+
+	if !strings.Contains(finalizer, "/") {
+		verbose().Infof("The finalizer %s has no domain prefix", finalizer)
+
+		builder.errorMsg = fmt.Sprintf("'finalizer' %s must have a domain prefix", finalizer)
+
+		return builder
+	}
+
+	if errs := validation.IsQualifiedName(finalizer); len(errs) > 0 {
+		verbose().Infof("The finalizer %s is invalid: %v", finalizer, errs)
+
+		builder.errorMsg = fmt.Sprintf("'finalizer' %s is invalid: %s", finalizer, strings.Join(errs, ", "))
+
+		return builder
+	}
+
+	for _, existingFinalizer := range builder.Definition.Finalizers {
+		if existingFinalizer == finalizer {
+			return builder
+		}
+	}
+
+	builder.Definition.Finalizers = append(builder.Definition.Finalizers, finalizer)
+
+	return builder
+}
+
+// WithLayeringEnabled sets or removes the pool label that opts the MachineConfigPool into on-cluster image
+// layering. The MCO only checks the presence of the label, so it is always written with an empty value.
+func (builder *MCPBuilder) WithLayeringEnabled(enabled bool) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting layering enabled to %t on MachineConfigPool %s", enabled, builder.Definition.Name)
+
+	if !enabled {
+		delete(builder.Definition.Labels, layeringEnabledPoolLabel)
+
+		return builder
+	}
+
+	if builder.Definition.Labels == nil {
+		builder.Definition.Labels = make(map[string]string)
+	}
+
+	builder.Definition.Labels[layeringEnabledPoolLabel] = ""
+
+	return builder
+}
+
+// ValidateDefinition checks locally, without any API call, that the MachineConfigPool definition has all
+// required fields set and that they are well-formed. Every missing or invalid field is listed in the error.
+func (builder *MCPBuilder) ValidateDefinition() error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Validating the MachineConfigPool %s definition", builder.Definition.Name)
+
+	var invalidFields []string
+
+	if builder.Definition.Name == "" {
+		invalidFields = append(invalidFields, "'name' cannot be empty")
+	} else if errs := validation.IsDNS1123Subdomain(builder.Definition.Name); len(errs) > 0 {
+		invalidFields = append(invalidFields, fmt.Sprintf("'name' is invalid: %s", strings.Join(errs, ", ")))
+	}
+
+	mcSelector := builder.Definition.Spec.MachineConfigSelector
+
+	switch {
+	case mcSelector == nil:
+		invalidFields = append(invalidFields, "'machineConfigSelector' cannot be empty")
+	case len(mcSelector.MatchLabels) == 0 && len(mcSelector.MatchExpressions) == 0 && !builder.emptyMcSelector:
+		invalidFields = append(invalidFields, "'machineConfigSelector' must have matchLabels or matchExpressions")
+	default:
+		if _, err := metav1.LabelSelectorAsSelector(mcSelector); err != nil {
+			invalidFields = append(invalidFields, fmt.Sprintf("'machineConfigSelector' is invalid: %v", err))
+		}
+	}
+
+	if builder.Definition.Spec.NodeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(builder.Definition.Spec.NodeSelector); err != nil {
+			invalidFields = append(invalidFields, fmt.Sprintf("'nodeSelector' is invalid: %v", err))
+		}
+	}
+
+	if len(invalidFields) > 0 {
+		verbose().Infof("The MachineConfigPool %s definition is invalid: %v", builder.Definition.Name, invalidFields)
+
+		return fmt.Errorf("invalid MachineConfigPool %s definition: %s",
+			builder.Definition.Name, strings.Join(invalidFields, "; "))
+	}
+
+	return nil
+}
+
+// ValidateForVersion checks that the MachineConfigPool definition only sets spec fields supported by the given
+// OpenShift version, e.g. "4.14" or "4.14.3". Every unsupported field is listed in the error. Fields the vendored
+// MachineConfigPool type does not know are read from the unstructured object the builder was created from.
+func (builder *MCPBuilder) ValidateForVersion(version string) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Validating the MachineConfigPool %s definition against OpenShift version %s",
+		builder.Definition.Name, version)
+
+	minor, err := parseOpenShiftMinorVersion(version)
+	if err != nil {
+		return err
+	}
+
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&builder.Definition.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to convert MachineConfigPool %s spec to unstructured: %w",
+			builder.Definition.Name, err)
+	}
+
+	var unsupportedFields []string
+
+	for field, minMinor := range versionGatedSpecFields {
+		value, found := spec[field]
+		if !found {
+			value, found = builder.unstructuredSpec[field]
+		}
+
+		if found && value != nil && minor < minMinor {
+			unsupportedFields = append(unsupportedFields,
+				fmt.Sprintf("'%s' requires OpenShift 4.%d or later", field, minMinor))
+		}
+	}
+
+	if len(unsupportedFields) > 0 {
+		sort.Strings(unsupportedFields)
+
+		return fmt.Errorf("MachineConfigPool %s definition is not supported by OpenShift %s: %s",
+			builder.Definition.Name, version, strings.Join(unsupportedFields, "; "))
+	}
+
+	return nil
+}
+
+// ValidateNoPoolOverlap checks that no node matched by the nodeSelector of the MachineConfigPool definition is
+// also matched by another MachineConfigPool, and returns an error naming the conflicting pool. The worker pool is
+// not checked, as the MCO lets a node belong to the worker pool and to one custom pool.
+func (builder *MCPBuilder) ValidateNoPoolOverlap(apiClient *clients.Settings) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	if apiClient == nil {
+		verbose().Infof("The apiClient is nil")
+
+		return fmt.Errorf("apiClient cannot be nil")
+	}
+
+	verbose().Infof("Validating that MachineConfigPool %s does not overlap other pools", builder.Definition.Name)
+
+	if builder.Definition.Spec.NodeSelector == nil {
+		return nil
+	}
+
+	nodeSelector, err := metav1.LabelSelectorAsSelector(builder.Definition.Spec.NodeSelector)
+	if err != nil {
+		return fmt.Errorf("invalid nodeSelector of MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	nodeList, err := apiClient.CoreV1Interface.Nodes().List(
+		ctx, metav1.ListOptions{LabelSelector: nodeSelector.String()})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes of MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	mcpList, err := apiClient.MachineConfigPools().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list MachineConfigPools: %w", err)
+	}
+
+	for _, mcp := range mcpList.Items {
+		if mcp.Name == builder.Definition.Name || mcp.Name == workerPoolName || mcp.Spec.NodeSelector == nil {
+			continue
+		}
+
+		otherSelector, err := metav1.LabelSelectorAsSelector(mcp.Spec.NodeSelector)
+		if err != nil {
+			return fmt.Errorf("invalid nodeSelector of MachineConfigPool %s: %w", mcp.Name, err)
+		}
+
+		for _, node := range nodeList.Items {
+			if otherSelector.Matches(labels.Set(node.Labels)) {
+				verbose().Infof("Node %s is matched by MachineConfigPools %s and %s",
+					node.Name, builder.Definition.Name, mcp.Name)
+
+				return fmt.Errorf("MachineConfigPool %s overlaps MachineConfigPool %s on node %s",
+					builder.Definition.Name, mcp.Name, node.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AllowReservedName allows Create to make a MachineConfigPool named like a built-in pool, master or worker.
+func (builder *MCPBuilder) AllowReservedName() *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Allowing reserved name for MachineConfigPool %s", builder.Definition.Name)
+
+	builder.allowReservedName = true
+
+	return builder
+}
+
+// WithSkipExistsCheck makes Create attempt the creation directly instead of checking first if the
+// MachineConfigPool exists. An already existing MachineConfigPool is fetched and not treated as an error.
+func (builder *MCPBuilder) WithSkipExistsCheck() *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Skipping exists check on create of MachineConfigPool %s", builder.Definition.Name)
+
+	builder.skipExistsCheck = true
+
+	return builder
+}
+
+// WithObserver sets a callback invoked on completion of every Create, Update, Delete and Wait operation of
+// the builder with the operation duration and result.
+func (builder *MCPBuilder) WithObserver(observer MCPObserver) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting observer of MachineConfigPool %s", builder.Definition.Name)
+
+	if observer == nil {
+		verbose().Infof("The observer cannot be nil")
+
+		builder.errorMsg = "'observer' cannot be nil"
+
+		return builder
+	}
+
+	builder.observer = observer
+
+	return builder
+}
+
+// WithPollInterval sets the interval at which the wait methods of the builder poll the MachineConfigPool.
+func (builder *MCPBuilder) WithPollInterval(interval time.Duration) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting MachineConfigPool %s poll interval to %v", builder.Definition.Name, interval)
+
+	if interval <= 0 {
+		verbose().Infof("The poll interval must be positive")
+
+		builder.errorMsg = fmt.Sprintf("'pollInterval' must be positive, got %v", interval)
+
+		return builder
+	}
+
+	builder.pollInterval = interval
+
+	return builder
+}
+
+// WithDefaultTimeout sets the timeout used by the wait methods of the builder that take no explicit timeout,
+// such as WaitForUpdateDefault.
+func (builder *MCPBuilder) WithDefaultTimeout(timeout time.Duration) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting MachineConfigPool %s default timeout to %v", builder.Definition.Name, timeout)
+
+	if timeout <= 0 {
+		verbose().Infof("The default timeout must be positive")
+
+		builder.errorMsg = fmt.Sprintf("'defaultTimeout' must be positive, got %v", timeout)
+
+		return builder
+	}
+
+	builder.defaultTimeout = timeout
+
+	return builder
+}
+
+// WithConversionScheme sets the scheme used by ToYAML and ToUnstructured to convert the MachineConfigPool
+// definition to the API version the scheme registers it under, e.g. for clusters running another MCO CRD version.
+func (builder *MCPBuilder) WithConversionScheme(scheme *runtime.Scheme) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting MachineConfigPool %s conversion scheme", builder.Definition.Name)
+
+	if scheme == nil {
+		verbose().Infof("The conversion scheme is nil")
+
+		builder.errorMsg = "MachineConfigPool conversion scheme cannot be nil"
+
+		return builder
+	}
+
+	if _, _, err := scheme.ObjectKinds(&mcov1.MachineConfigPool{}); err != nil {
+		verbose().Infof("The conversion scheme does not know MachineConfigPool: %v", err)
+
+		builder.errorMsg = fmt.Sprintf("conversion scheme does not know MachineConfigPool: %v", err)
+
+		return builder
+	}
+
+	builder.conversionScheme = scheme
+
+	return builder
+}
+
+// WithContext sets the parent context of the API calls made by Create, Update, Delete and Exists. The
+// context replaces the default per-call timeout, so its deadline or cancellation bounds these calls instead.
+func (builder *MCPBuilder) WithContext(ctx context.Context) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting MachineConfigPool %s context", builder.Definition.Name)
+
+	if ctx == nil {
+		verbose().Infof("The context cannot be nil")
+
+		builder.errorMsg = "'ctx' cannot be nil"
+
+		return builder
+	}
+
+	builder.ctx = ctx
+
+	return builder
+}
+
+// WaitToBeInCondition waits for a specific time duration until the MachineConfigPool will have a
+// specified condition type with the expected status.
+func (builder *MCPBuilder) WaitToBeInCondition(
+	conditionType mcov1.MachineConfigPoolConditionType,
+	conditionStatus corev1.ConditionStatus,
+	timeout time.Duration,
+) (err error) {
+	defer builder.observe("WaitToBeInCondition", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitToBeInCondition waits up to specified time duration %v until "+
+		"MachineConfigPool condition %v is met", timeout, conditionType)
+
+	// the builder may not have been pulled or created yet, so the object is looked up by the definition name and
+	// stored on every poll.
+	return wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		for _, condition := range builder.Object.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == conditionStatus {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+}
+
+// WaitForConditionStatus waits for a specific time duration until the given condition type of the MachineConfigPool
+// has the expected status. Unlike WaitToBeInCondition, waiting for the False status also succeeds when the
+// MachineConfigPool does not report the condition at all, e.g. to confirm that a pool is not degraded.
+func (builder *MCPBuilder) WaitForConditionStatus(
+	conditionType mcov1.MachineConfigPoolConditionType,
+	conditionStatus corev1.ConditionStatus,
+	timeout time.Duration,
+) (err error) {
+	defer builder.observe("WaitForConditionStatus", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForConditionStatus waits up to specified time duration %v until MachineConfigPool %s "+
+		"condition %v is %v", timeout, builder.Definition.Name, conditionType, conditionStatus)
+
+	return wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		for _, condition := range builder.Object.Status.Conditions {
+			if condition.Type == conditionType {
+				return condition.Status == conditionStatus, nil
+			}
+		}
+
+		verbose().Infof("MachineConfigPool %s does not report condition %v", builder.Definition.Name, conditionType)
+
+		return conditionStatus == corev1.ConditionFalse, nil
+	})
+}
+
+// WaitForUpdateMultiple waits concurrently for all given MachineConfigPools to be updating and then updated.
+// The errors of all MachineConfigPools that failed to update are aggregated in an MCPAggregateError.
+func WaitForUpdateMultiple(pools []*MCPBuilder, timeout time.Duration) error {
+	verbose().Infof("WaitForUpdateMultiple waits up to specified time %v until %d MachineConfigPools are updated",
+		timeout, len(pools))
+
+	poolErrors := make([]error, len(pools))
+	errGroup := errgroup.Group{}
+
+	// invalid builders are rejected before any wait starts, so that no goroutine runs on them.
+	hasInvalidPool := false
+
+	for index, pool := range pools {
+		if valid, err := pool.validate(); !valid {
+			poolErrors[index] = err
+			hasInvalidPool = true
+		}
+	}
+
+	if hasInvalidPool {
+		return newWaitForUpdateMultipleError(pools, poolErrors)
+	}
+
+	for index, pool := range pools {
+		index, pool := index, pool
+
+		errGroup.Go(func() error {
+			poolErrors[index] = pool.WaitForUpdate(timeout)
+
+			return poolErrors[index]
+		})
+	}
+
+	if errGroup.Wait() == nil {
+		return nil
+	}
+
+	return newWaitForUpdateMultipleError(pools, poolErrors)
+}
+
+// newWaitForUpdateMultipleError returns the MCPAggregateError of WaitForUpdateMultiple holding the non-nil errors
+// of the given pools.
+func newWaitForUpdateMultipleError(pools []*MCPBuilder, poolErrors []error) *MCPAggregateError {
+	aggregateError := &MCPAggregateError{Operation: "wait for MachineConfigPools to be updated"}
+
+	for index, err := range poolErrors {
+		if err == nil {
+			continue
+		}
+
+		poolName := "<nil>"
+		if pools[index] != nil && pools[index].Definition != nil {
+			poolName = pools[index].Definition.Name
+		}
+
+		aggregateError.Errors = append(aggregateError.Errors, MCPPoolError{Pool: poolName, Err: err})
+	}
+
+	return aggregateError
+}
+
+// MCPAggregateError holds the errors of the MachineConfigPools that failed a multi-pool operation, so that callers
+// can tell which pools failed.
+type MCPAggregateError struct {
+	// Operation describes the multi-pool operation that failed.
+	Operation string
+	// Errors holds the error of every failed MachineConfigPool, in the order the pools were processed. Invalid
+	// builders and pools sharing a name get an entry each.
+	Errors []MCPPoolError
+}
+
+// MCPPoolError is the error of a single MachineConfigPool of a multi-pool operation.
+type MCPPoolError struct {
+	// Pool is the name of the MachineConfigPool, or <nil> for an uninitialized builder.
+	Pool string
+	// Err is the error of the MachineConfigPool.
+	Err error
+}
+
+// Error returns the errors of all failed MachineConfigPools.
+func (aggregateError *MCPAggregateError) Error() string {
+	errorMessages := make([]string, 0, len(aggregateError.Errors))
+
+	for _, poolError := range aggregateError.Errors {
+		errorMessages = append(errorMessages, fmt.Sprintf("%s: %v", poolError.Pool, poolError.Err))
+	}
+
+	return fmt.Sprintf("failed to %s: %s", aggregateError.Operation, strings.Join(errorMessages, "; "))
+}
+
+// Is returns true if the error of any failed MachineConfigPool matches target. errors.Is only follows
+// Unwrap() []error from Go 1.20 on, so the errors are matched explicitly.
+func (aggregateError *MCPAggregateError) Is(target error) bool {
+	for _, poolError := range aggregateError.Errors {
+		if errors.Is(poolError.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error of the failed MachineConfigPools that matches target and sets target to it. errors.As
+// only follows Unwrap() []error from Go 1.20 on, so the errors are matched explicitly.
+func (aggregateError *MCPAggregateError) As(target interface{}) bool {
+	for _, poolError := range aggregateError.Errors {
+		if errors.As(poolError.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Unwrap returns the errors of all failed MachineConfigPools.
+func (aggregateError *MCPAggregateError) Unwrap() []error {
+	errs := make([]error, 0, len(aggregateError.Errors))
+
+	for _, poolError := range aggregateError.Errors {
+		errs = append(errs, poolError.Err)
+	}
+
+	return errs
+}
+
+// WaitForDegradedThenRecover waits up to degradeTimeout until the MachineConfigPool becomes degraded and then
+// up to recoverTimeout until the MachineConfigPool is not degraded anymore.
+func (builder *MCPBuilder) WaitForDegradedThenRecover(degradeTimeout, recoverTimeout time.Duration) (err error) {
+	defer builder.observe("WaitForDegradedThenRecover", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForDegradedThenRecover waits up to %v until MachineConfigPool %s is degraded and "+
+		"then up to %v until it recovers", degradeTimeout, builder.Definition.Name, recoverTimeout)
+
+	err = builder.WaitToBeInCondition(mcov1.MachineConfigPoolDegraded, corev1.ConditionTrue, degradeTimeout)
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s did not become degraded within %v: %w",
+			builder.Definition.Name, degradeTimeout, err)
+	}
+
+	err = builder.WaitToBeInCondition(mcov1.MachineConfigPoolDegraded, corev1.ConditionFalse, recoverTimeout)
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s did not recover from degraded within %v: %w",
+			builder.Definition.Name, recoverTimeout, err)
+	}
+
+	return nil
+}
+
+// WaitForUpdate waits for a MachineConfigPool to be updating and then updated.
+func (builder *MCPBuilder) WaitForUpdate(timeout time.Duration) (err error) {
+	defer builder.observe("WaitForUpdate", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForUpdate waits up to specified time %v until updating"+
+		" machineConfigPool object is updated", timeout)
+
+	// the builder may not have been pulled or created yet, so the object is looked up by the definition name.
+	if err := builder.refresh(); err != nil {
+		return err
+	}
+
+	if !isInConditionStatus(builder.Object, mcov1.MachineConfigPoolUpdating, isTrue) {
+		return nil
+	}
+
+	return wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		return isInConditionStatus(builder.Object, mcov1.MachineConfigPoolUpdated, isTrue), nil
+	})
+}
+
+// WaitForUpdateWithProgress waits for a specific time duration until the MachineConfigPool reports the Updated
+// condition as True. The onProgress function is called on every poll with the updated and total machine counts.
+func (builder *MCPBuilder) WaitForUpdateWithProgress(
+	timeout time.Duration, onProgress func(updated, total int32)) (err error) {
+	defer builder.observe("WaitForUpdateWithProgress", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	if onProgress == nil {
+		verbose().Infof("The onProgress function of MachineConfigPool %s is nil", builder.Definition.Name)
+
+		return fmt.Errorf("MachineConfigPool %s onProgress function cannot be nil", builder.Definition.Name)
+	}
+
+	verbose().Infof("WaitForUpdateWithProgress waits up to specified time %v until MachineConfigPool %s "+
+		"is updated", timeout, builder.Definition.Name)
+
+	err = wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		onProgress(builder.Object.Status.UpdatedMachineCount, builder.Object.Status.MachineCount)
+
+		for _, condition := range builder.Object.Status.Conditions {
+			if condition.Type == mcov1.MachineConfigPoolUpdated && condition.Status == corev1.ConditionTrue {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s was not updated: %w", builder.Definition.Name, err)
+	}
+
+	return nil
+}
+
+// WaitForUpdateAndVerify waits for a specific time duration until the MachineConfigPool is updated and then runs
+// the given verify function against every node of the MachineConfigPool. The failures of all nodes are aggregated.
+func (builder *MCPBuilder) WaitForUpdateAndVerify(verify func(node *corev1.Node) error, timeout time.Duration) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForUpdateAndVerify waits up to specified time %v until MachineConfigPool %s is updated "+
+		"and verifies its nodes", timeout, builder.Definition.Name)
+
+	if verify == nil {
+		verbose().Infof("The verify function cannot be nil")
+
+		return fmt.Errorf("'verify' function cannot be nil")
+	}
+
+	err := builder.WaitToBeInCondition(mcov1.MachineConfigPoolUpdated, corev1.ConditionTrue, timeout)
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s failed to update: %w", builder.Definition.Name, err)
+	}
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return err
+	}
+
+	var errorMessages []string
+
+	for index := range poolNodes {
+		if err := verify(&poolNodes[index]); err != nil {
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %v", poolNodes[index].Name, err))
+		}
+	}
+
+	if len(errorMessages) > 0 {
+		return fmt.Errorf("verification of MachineConfigPool %s nodes failed: %s",
+			builder.Definition.Name, strings.Join(errorMessages, "; "))
+	}
+
+	return nil
+}
+
+// WaitForUpdateDefault waits for a MachineConfigPool to be updating and then updated, using the timeout set with
+// WithDefaultTimeout.
+func (builder *MCPBuilder) WaitForUpdateDefault() error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	if builder.defaultTimeout <= 0 {
+		verbose().Infof("The MachineConfigPool %s has no default timeout", builder.Definition.Name)
+
+		return fmt.Errorf("MachineConfigPool %s has no default timeout, use WithDefaultTimeout",
+			builder.Definition.Name)
+	}
+
+	return builder.WaitForUpdate(builder.defaultTimeout)
+}
+
+// WaitForUpdateWatch waits up to the given timeout until the MachineConfigPool is updated. The MachineConfigPool
+// is watched and its conditions are checked on every event. Polling is used if the watch drops.
+func (builder *MCPBuilder) WaitForUpdateWatch(timeout time.Duration) (err error) {
+	defer builder.observe("WaitForUpdateWatch", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForUpdateWatch watches up to specified time %v until MachineConfigPool %s is updated",
+		timeout, builder.Definition.Name)
+
+	return builder.WaitToBeInConditionWatch(mcov1.MachineConfigPoolUpdated, corev1.ConditionTrue, timeout)
+}
+
+// WaitToBeInConditionWatch waits up to the given timeout until the MachineConfigPool has the given condition type
+// with the expected status. The MachineConfigPool is watched for status updates. Polling is used if the watch drops.
+func (builder *MCPBuilder) WaitToBeInConditionWatch(
+	conditionType mcov1.MachineConfigPoolConditionType,
+	conditionStatus corev1.ConditionStatus,
+	timeout time.Duration,
+) (err error) {
+	defer builder.observe("WaitToBeInConditionWatch", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitToBeInConditionWatch watches up to specified time %v until MachineConfigPool %s "+
+		"condition %v is %v", timeout, builder.Definition.Name, conditionType, conditionStatus)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	watched, err := builder.watchUntil(ctx, "", func(event watch.Event) (bool, error) {
+		if event.Type == watch.Deleted {
+			return false, fmt.Errorf("MachineConfigPool %s was deleted", builder.Definition.Name)
+		}
+
+		mcp, ok := event.Object.(*mcov1.MachineConfigPool)
+		if !ok {
+			return false, nil
+		}
+
+		return isInConditionStatus(mcp, conditionType, conditionStatus), nil
+	})
+
+	if watched {
+		return err
+	}
+
+	verbose().Infof("Falling back to polling until MachineConfigPool %s condition %v is %v",
+		builder.Definition.Name, conditionType, conditionStatus)
+
+	deadline, _ := ctx.Deadline()
+
+	return builder.WaitToBeInCondition(conditionType, conditionStatus, time.Until(deadline))
+}
+
+// WaitForDeletionWatch waits up to the given timeout until the MachineConfigPool is deleted. The MachineConfigPool
+// is watched for a deletion event. Polling is used if the watch drops. Only a NotFound response counts as deleted,
+// the error of any other failed request is returned by the initial check and retried while polling.
+func (builder *MCPBuilder) WaitForDeletionWatch(timeout time.Duration) (err error) {
+	defer builder.observe("WaitForDeletionWatch", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForDeletionWatch watches up to specified time %v until MachineConfigPool %s is deleted",
+		timeout, builder.Definition.Name)
+
+	exists, err := builder.exists()
+	if err != nil {
+		return fmt.Errorf("cannot check if MachineConfigPool %s exists: %w", builder.Definition.Name, err)
+	}
+
+	if !exists {
+		verbose().Infof("The MachineConfigPool %s is already deleted", builder.Definition.Name)
+
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Watch from the resourceVersion fetched by exists so that a deletion in between is not missed.
+	watched, err := builder.watchUntil(ctx, builder.Object.ResourceVersion, func(event watch.Event) (bool, error) {
+		return event.Type == watch.Deleted, nil
+	})
+
+	if watched {
+		return err
+	}
+
+	verbose().Infof("Falling back to polling until MachineConfigPool %s is deleted", builder.Definition.Name)
+
+	deadline, _ := ctx.Deadline()
+
+	return wait.PollImmediate(builder.getPollInterval(), time.Until(deadline), func() (bool, error) {
+		exists, err := builder.exists()
+		if err != nil {
+			verbose().Infof("Failed to check if MachineConfigPool %s exists: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		return !exists, nil
+	})
+}
+
+// WaitForUpdatedMachineCount waits for a specific time duration until at least the given number of
+// machines in the MachineConfigPool are updated.
+func (builder *MCPBuilder) WaitForUpdatedMachineCount(count int32, timeout time.Duration) (err error) {
+	defer builder.observe("WaitForUpdatedMachineCount", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForUpdatedMachineCount waits up to specified time %v until %d machines "+
+		"of MachineConfigPool %s are updated", timeout, count, builder.Definition.Name)
+
+	if count < 0 {
+		verbose().Infof("The updated machine count cannot be negative")
+
+		return fmt.Errorf("updated machine count cannot be negative, got %d", count)
+	}
+
+	return wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		verbose().Infof("MachineConfigPool %s has %d of %d updated machines", builder.Definition.Name,
+			builder.Object.Status.UpdatedMachineCount, builder.Object.Status.MachineCount)
+
+		return builder.Object.Status.UpdatedMachineCount >= count, nil
+	})
+}
+
+// WaitForReadyMachineCount waits for a specific time duration until at least the given number of
+// machines in the MachineConfigPool are ready, e.g. to confirm the pool is back to full capacity after maintenance.
+func (builder *MCPBuilder) WaitForReadyMachineCount(count int32, timeout time.Duration) (err error) {
+	defer builder.observe("WaitForReadyMachineCount", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForReadyMachineCount waits up to specified time %v until %d machines "+
+		"of MachineConfigPool %s are ready", timeout, count, builder.Definition.Name)
+
+	if count < 0 {
+		verbose().Infof("The ready machine count cannot be negative")
+
+		return fmt.Errorf("ready machine count cannot be negative, got %d", count)
+	}
+
+	return wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		verbose().Infof("MachineConfigPool %s has %d of %d ready machines", builder.Definition.Name,
+			builder.Object.Status.ReadyMachineCount, builder.Object.Status.MachineCount)
+
+		return builder.Object.Status.ReadyMachineCount >= count, nil
+	})
+}
+
+// WaitForEmpty waits for a specific time duration until the MachineConfigPool has no machines left, e.g. before
+// deleting a custom pool whose nodes were moved out.
+func (builder *MCPBuilder) WaitForEmpty(timeout time.Duration) (err error) {
+	defer builder.observe("WaitForEmpty", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForEmpty waits up to specified time %v until MachineConfigPool %s has no machines",
+		timeout, builder.Definition.Name)
+
+	return wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		verbose().Infof("MachineConfigPool %s has %d machines", builder.Definition.Name,
+			builder.Object.Status.MachineCount)
+
+		return builder.Object.Status.MachineCount == 0, nil
+	})
+}
+
+// WaitForNoUpdatingNodes waits for a specific time duration until no node of the MachineConfigPool has the
+// machine-config-daemon Working state.
+func (builder *MCPBuilder) WaitForNoUpdatingNodes(timeout time.Duration) (err error) {
+	defer builder.observe("WaitForNoUpdatingNodes", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForNoUpdatingNodes waits up to specified time %v until no node of MachineConfigPool %s "+
+		"is updating", timeout, builder.Definition.Name)
+
+	return wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		poolNodes, err := builder.getPoolNodes()
+		if err != nil {
+			return false, nil
+		}
+
+		for _, node := range poolNodes {
+			if node.Annotations[daemonconsts.MachineConfigDaemonStateAnnotationKey] ==
+				daemonconsts.MachineConfigDaemonStateWorking {
+				verbose().Infof("Node %s of MachineConfigPool %s is still updating", node.Name, builder.Definition.Name)
+
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+}
+
+// WaitForMachineCountToMatchNodes waits for a specific time duration until the machineCount of the
+// MachineConfigPool equals the number of nodes matching its nodeSelector.
+func (builder *MCPBuilder) WaitForMachineCountToMatchNodes(timeout time.Duration) (err error) {
+	defer builder.observe("WaitForMachineCountToMatchNodes", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForMachineCountToMatchNodes waits up to specified time %v until MachineConfigPool %s "+
+		"machineCount matches its nodes", timeout, builder.Definition.Name)
+
+	var machineCount, nodeCount int
+
+	err = wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		poolNodes, err := builder.getPoolNodes()
+		if err != nil {
+			return false, nil
+		}
+
+		machineCount = int(builder.Object.Status.MachineCount)
+		nodeCount = len(poolNodes)
+
+		return machineCount == nodeCount, nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s machineCount %d does not match the %d nodes selected: %w",
+			builder.Definition.Name, machineCount, nodeCount, err)
+	}
+
+	return nil
+}
+
+// WaitForMachineCountChange waits for a specific time duration until the machineCount of the MachineConfigPool
+// differs from the given count and returns the new machineCount, e.g. to confirm a scale event.
+func (builder *MCPBuilder) WaitForMachineCountChange(from int32, timeout time.Duration) (_ int32, err error) {
+	defer builder.observe("WaitForMachineCountChange", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return 0, err
+	}
+
+	verbose().Infof("WaitForMachineCountChange waits up to specified time %v until MachineConfigPool %s "+
+		"machineCount changes from %d", timeout, builder.Definition.Name, from)
+
+	var machineCount int32
+
+	err = wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		machineCount = builder.Object.Status.MachineCount
+
+		return machineCount != from, nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("MachineConfigPool %s machineCount did not change from %d: %w",
+			builder.Definition.Name, from, err)
+	}
+
+	return machineCount, nil
+}
+
+// WaitForNodesReady waits for a specific time duration until every node of the MachineConfigPool reports the
+// kubelet Ready condition as True.
+func (builder *MCPBuilder) WaitForNodesReady(timeout time.Duration) (err error) {
+	defer builder.observe("WaitForNodesReady", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForNodesReady waits up to specified time %v until all nodes of MachineConfigPool %s "+
+		"are Ready", timeout, builder.Definition.Name)
+
+	return wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		poolNodes, err := builder.getPoolNodes()
+		if err != nil {
+			return false, nil
+		}
+
+		for _, poolNode := range poolNodes {
+			if !isNodeReady(&poolNode) {
+				verbose().Infof("Node %s of MachineConfigPool %s is not Ready", poolNode.Name, builder.Definition.Name)
+
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+}
+
+// WaitForConfigurationName waits for a specific time duration until the MachineConfigPool status reports the
+// rendered MachineConfig with the given name as its configuration.
+func (builder *MCPBuilder) WaitForConfigurationName(name string, timeout time.Duration) (err error) {
+	defer builder.observe("WaitForConfigurationName", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForConfigurationName waits up to specified time %v until MachineConfigPool %s "+
+		"configuration is %s", timeout, builder.Definition.Name, name)
+
+	if name == "" {
+		verbose().Infof("The configuration name cannot be empty")
+
+		return fmt.Errorf("configuration 'name' cannot be empty")
+	}
+
+	var observedName string
+
+	err = wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		observedName = builder.Object.Status.Configuration.Name
+
+		return observedName == name, nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s configuration is %s instead of %s: %w",
+			builder.Definition.Name, observedName, name, err)
+	}
+
+	return nil
+}
+
+// WaitForObservedGeneration waits for a specific time duration until the MachineConfigPool controller has
+// observed at least the given generation. The generation of an updated MachineConfigPool is available in
+// builder.Object.Generation right after Update.
+func (builder *MCPBuilder) WaitForObservedGeneration(generation int64, timeout time.Duration) (err error) {
+	defer builder.observe("WaitForObservedGeneration", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForObservedGeneration waits up to specified time %v until MachineConfigPool %s "+
+		"observed generation %d", timeout, builder.Definition.Name, generation)
+
+	if generation <= 0 {
+		verbose().Infof("The generation must be positive")
+
+		return fmt.Errorf("generation must be positive, got %d", generation)
+	}
+
+	return wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		verbose().Infof("MachineConfigPool %s observed generation is %d", builder.Definition.Name,
+			builder.Object.Status.ObservedGeneration)
+
+		return builder.Object.Status.ObservedGeneration >= generation, nil
+	})
+}
+
+// WaitForStableAtGeneration waits up to the given timeout until the MachineConfigPool controller has observed
+// at least the given generation and the MachineConfigPool then stays stable for stableDuration.
+func (builder *MCPBuilder) WaitForStableAtGeneration(
+	generation int64, stableDuration, timeout time.Duration) (err error) {
+	defer builder.observe("WaitForStableAtGeneration", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForStableAtGeneration waits up to %v until MachineConfigPool %s observed generation %d "+
+		"and is stable for %v", timeout, builder.Definition.Name, generation, stableDuration)
+
+	deadline := time.Now().Add(timeout)
+
+	err = builder.WaitForObservedGeneration(generation, timeout)
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s did not observe generation %d: %w",
+			builder.Definition.Name, generation, err)
+	}
+
+	err = builder.WaitToBeStableFor(stableDuration, time.Until(deadline))
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s was not stable for %v at generation %d: %w",
+			builder.Definition.Name, stableDuration, generation, err)
+	}
+
+	return nil
+}
+
+// StabilityReport describes how waiting for a MachineConfigPool to be stable settled or failed.
+type StabilityReport struct {
+	// Stable is true when the MachineConfigPool stayed stable during the whole stable duration.
+	Stable bool
+	// Iterations is the number of stable duration windows observed during the wait.
+	Iterations int
+	// LastMachineCount is the last observed machineCount of the MachineConfigPool.
+	LastMachineCount int32
+	// LastReady is the last observed readyMachineCount of the MachineConfigPool.
+	LastReady int32
+	// LastDegraded is the last observed degradedMachineCount of the MachineConfigPool.
+	LastDegraded int32
+}
+
+// WaitToBeStableFor waits on MachineConfigPool to stable for a time duration or until timeout.
+func (builder *MCPBuilder) WaitToBeStableFor(stableDuration time.Duration, timeout time.Duration) error {
+	_, err := builder.WaitToBeStableForReport(stableDuration, timeout)
+
+	return err
+}
+
+// WaitForUpdateThenStable waits for a MachineConfigPool to be updated and then to stay stable for stableDuration.
+// The timeout covers both phases, and the returned error names the phase that failed.
+func (builder *MCPBuilder) WaitForUpdateThenStable(stableDuration, timeout time.Duration) (err error) {
+	defer builder.observe("WaitForUpdateThenStable", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitForUpdateThenStable waits up to specified time %v until MachineConfigPool %s is updated "+
+		"and stable for %v", timeout, builder.Definition.Name, stableDuration)
+
+	deadline := time.Now().Add(timeout)
+
+	err = builder.WaitForUpdate(timeout)
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s failed to update: %w", builder.Definition.Name, err)
+	}
+
+	err = builder.WaitToBeStableFor(stableDuration, time.Until(deadline))
+	if err != nil {
+		return fmt.Errorf("MachineConfigPool %s updated but did not stay stable for %v: %w",
+			builder.Definition.Name, stableDuration, err)
+	}
+
+	return nil
+}
+
+// WaitToBeStableForReport waits on MachineConfigPool to stable for a time duration or until timeout
+// and returns a report describing the observed MachineConfigPool state.
+func (builder *MCPBuilder) WaitToBeStableForReport(
+	stableDuration time.Duration, timeout time.Duration) (_ *StabilityReport, err error) {
+	defer builder.observe("WaitToBeStableFor", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("WaitToBeStableFor waits up to duration of %v for "+
+		"MachineConfigPool to be stable for %v", timeout, stableDuration)
+
+	return builder.waitToBeStable(0, stableDuration, timeout)
+}
+
+// WaitToBeStableWithTolerance waits on MachineConfigPool to stable for a time duration or until timeout, accepting
+// up to maxDegraded degraded machines. All machines that are not degraded must be ready and updated.
+func (builder *MCPBuilder) WaitToBeStableWithTolerance(
+	maxDegraded int32, stableDuration, timeout time.Duration) (err error) {
+	defer builder.observe("WaitToBeStableWithTolerance", time.Now(), &err)
+
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("WaitToBeStableWithTolerance waits up to duration of %v for MachineConfigPool %s to be stable "+
+		"for %v with up to %d degraded machines", timeout, builder.Definition.Name, stableDuration, maxDegraded)
+
+	if maxDegraded < 0 {
+		verbose().Infof("The maximum number of degraded machines cannot be negative")
+
+		return fmt.Errorf("maximum number of degraded machines cannot be negative, got %d", maxDegraded)
+	}
+
+	_, err = builder.waitToBeStable(maxDegraded, stableDuration, timeout)
+
+	return err
+}
+
+// waitToBeStable waits on MachineConfigPool to stable for a time duration or until timeout, accepting up to
+// maxDegraded degraded machines, and returns a report describing the observed MachineConfigPool state.
+func (builder *MCPBuilder) waitToBeStable(
+	maxDegraded int32, stableDuration, timeout time.Duration) (*StabilityReport, error) {
+	report := &StabilityReport{}
+
+	// Wait the poll interval in each iteration before condition function () returns true or errors
+	// or times out after stableDuration
+	err := wait.PollImmediate(builder.getPollInterval(), timeout, func() (bool, error) {
+
+		report.Stable = true
+		report.Iterations++
+
+		_ = wait.PollImmediate(builder.getPollInterval(), stableDuration, func() (done bool, err error) {
+
+			// a MachineConfigPool that cannot be fetched is not known to be stable.
+			if err := builder.refresh(); err != nil {
+				verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+				report.Stable = false
+
+				return true, nil
+			}
+
+			report.LastMachineCount = builder.Object.Status.MachineCount
+			report.LastReady = builder.Object.Status.ReadyMachineCount
+			report.LastDegraded = builder.Object.Status.DegradedMachineCount
+
+			if !isStableStatus(&builder.Object.Status, maxDegraded) {
+
+				verbose().Infof("MachineConfigPool: %v degraded and has a mismatch in "+
+					"machineCount: %v "+"vs machineCountUpdated: "+"%v vs readyMachineCount: %v and "+
+					"degradedMachineCount is : %v \n", builder.Object.ObjectMeta.Name,
+					builder.Object.Status.MachineCount, builder.Object.Status.UpdatedMachineCount,
+					builder.Object.Status.ReadyMachineCount, builder.Object.Status.DegradedMachineCount)
+
+				report.Stable = false
+
+				return true, nil
+			}
+
+			return false, nil
+		})
+
+		if report.Stable {
+			verbose().Infof("MachineConfigPool was stable during during stableDuration: %v",
+				stableDuration)
+
+			// this will exit the outer wait.PollImmediate block since the mcp was stable during stableDuration
+			return true, nil
+		}
+
+		verbose().Infof("MachineConfigPool was not stable during stableDuration: %v, retrying ...",
+			stableDuration)
+
+		// keep iterating in the outer wait.PollImmediate waiting for cluster to be stable
+		return false, nil
+	})
+
+	// After the timout in outer wait.PollImmediate.
+	if err == nil {
+		verbose().Infof("Cluster was stable during stableDuration: %v", stableDuration)
+	} else {
+		// Here err is "timed out waiting for the condition"
+		verbose().Infof("Cluster was Un-stable during stableDuration: %v", stableDuration)
+
+		report.Stable = false
+	}
+
+	return report, err
+}
+
+// WithOptions creates mcp with generic mutation options.
+func (builder *MCPBuilder) WithOptions(options ...MCPAdditionalOptions) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Setting mcp additional options")
+
+	for _, option := range options {
+		if option != nil {
+			builder, err := option(builder)
+
+			if err != nil {
+				verbose().Infof("Error occurred in mutation function")
+
+				builder.errorMsg = err.Error()
+
+				return builder
+			}
+		}
+	}
+
+	return builder
+}
+
+// WithMutation applies the given function to the MachineConfigPool definition, e.g. for one-off field edits that
+// have no dedicated With method. The definition must keep its name.
+func (builder *MCPBuilder) WithMutation(mutate func(definition *mcov1.MachineConfigPool)) *MCPBuilder {
+	if valid, _ := builder.validate(); !valid {
+		return builder
+	}
+
+	verbose().Infof("Applying mutation to MachineConfigPool %s definition", builder.Definition.Name)
+
+	if mutate == nil {
+		verbose().Infof("The mutation function cannot be nil")
+
+		builder.errorMsg = "mutation function cannot be nil"
+
+		return builder
+	}
+
+	name := builder.Definition.Name
+
+	mutate(builder.Definition)
+
+	if builder.Definition.Name != name {
+		verbose().Infof("The mutation function changed the MachineConfigPool name %s", name)
+
+		builder.errorMsg = fmt.Sprintf("mutation function cannot change MachineConfigPool name %s", name)
+	}
+
+	return builder
+}
+
+// IsInCondition parses MachineConfigPool conditions.
+// Returns true if given MachineConfigPool is in given condition, otherwise false.
+func (builder *MCPBuilder) IsInCondition(mcpConditionType mcov1.MachineConfigPoolConditionType) bool {
+	if valid, _ := builder.validate(); !valid {
+		return false
+	}
+
+	verbose().Infof("IsInCondition returns true"+
+		" if MachineConfigPool object is in a given condition %v, otherwise false", mcpConditionType)
+
+	if builder.refresh() == nil {
+		for _, condition := range builder.Object.Status.Conditions {
+			if condition.Type == mcpConditionType && condition.Status == isTrue {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// GetMaxUnavailable returns the maxUnavailable value of the MachineConfigPool object, nil if it is not set.
+func (builder *MCPBuilder) GetMaxUnavailable() (*intstr.IntOrString, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting maxUnavailable of the MachineConfigPool %s", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	return builder.Object.Spec.MaxUnavailable, nil
+}
+
+// GetMcSelectorFull returns a copy of the machineConfigSelector of the MachineConfigPool object, including both
+// matchLabels and matchExpressions.
+func (builder *MCPBuilder) GetMcSelectorFull() (*metav1.LabelSelector, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting machineConfigSelector of the MachineConfigPool %s", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	return builder.Object.Spec.MachineConfigSelector.DeepCopy(), nil
+}
+
+// GetEffectiveMaxUnavailable returns the number of machines of the MachineConfigPool the MCO updates at once,
+// resolving maxUnavailable against the current machineCount the same way the MCO does: percentages are rounded
+// down, at least one machine is updated and an unset maxUnavailable defaults to 1.
+func (builder *MCPBuilder) GetEffectiveMaxUnavailable() (int32, error) {
+	if valid, err := builder.validate(); !valid {
+		return 0, err
+	}
+
+	verbose().Infof("Getting effective maxUnavailable of the MachineConfigPool %s", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return 0, err
+	}
+
+	maxUnavailable := intstr.FromInt(1)
+	if builder.Object.Spec.MaxUnavailable != nil {
+		maxUnavailable = *builder.Object.Spec.MaxUnavailable
+	}
+
+	machines, err := intstr.GetScaledValueFromIntOrPercent(
+		&maxUnavailable, int(builder.Object.Status.MachineCount), false)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxUnavailable %s of MachineConfigPool %s: %w",
+			maxUnavailable.String(), builder.Definition.Name, err)
+	}
+
+	if machines == 0 {
+		machines = 1
+	}
+
+	return int32(machines), nil
+}
+
+// GetStatus returns a copy of the status of the MachineConfigPool object.
+func (builder *MCPBuilder) GetStatus() (*mcov1.MachineConfigPoolStatus, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting the status of the MachineConfigPool %s", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	return builder.Object.Status.DeepCopy(), nil
+}
+
+// GetConditionsMap returns the conditions of the MachineConfigPool object keyed by condition type.
+func (builder *MCPBuilder) GetConditionsMap() (
+	map[mcov1.MachineConfigPoolConditionType]mcov1.MachineConfigPoolCondition, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting the conditions of the MachineConfigPool %s", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	conditions := make(map[mcov1.MachineConfigPoolConditionType]mcov1.MachineConfigPoolCondition,
+		len(builder.Object.Status.Conditions))
+
+	for _, condition := range builder.Object.Status.Conditions {
+		conditions[condition.Type] = *condition.DeepCopy()
+	}
+
+	return conditions, nil
+}
+
+// IsPaused returns true if the MachineConfigPool object is paused, otherwise false.
+func (builder *MCPBuilder) IsPaused() (bool, error) {
+	if valid, err := builder.validate(); !valid {
+		return false, err
+	}
+
+	verbose().Infof("Checking if the MachineConfigPool %s is paused", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return false, err
+	}
+
+	return builder.Object.Spec.Paused, nil
+}
+
+// GetRemainingNodes returns the number of machines of the MachineConfigPool that are not updated yet.
+func (builder *MCPBuilder) GetRemainingNodes() (int32, error) {
+	if valid, err := builder.validate(); !valid {
+		return 0, err
+	}
+
+	verbose().Infof("Getting the number of remaining machines to update in MachineConfigPool %s",
+		builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return 0, err
+	}
+
+	remaining := builder.Object.Status.MachineCount - builder.Object.Status.UpdatedMachineCount
+
+	// updatedMachineCount may transiently exceed machineCount while nodes leave the pool.
+	if remaining < 0 {
+		return 0, nil
+	}
+
+	return remaining, nil
+}
+
+// GetReadyPercentage returns the percentage of ready machines in the MachineConfigPool. A MachineConfigPool
+// without machines is reported as 0 percent ready.
+func (builder *MCPBuilder) GetReadyPercentage() (float64, error) {
+	if valid, err := builder.validate(); !valid {
+		return 0, err
+	}
+
+	verbose().Infof("Getting the percentage of ready machines in MachineConfigPool %s", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return 0, err
+	}
+
+	if builder.Object.Status.MachineCount == 0 {
+		return 0, nil
+	}
+
+	return float64(builder.Object.Status.ReadyMachineCount) / float64(builder.Object.Status.MachineCount) * 100, nil
+}
+
+// SampleRolloutRate samples the updated machine count of the MachineConfigPool every interval for the given
+// duration and returns the rollout rate in updated machines per minute. A drop of the count between two samples,
+// when a new rendered config starts rolling out, is not counted as progress.
+func (builder *MCPBuilder) SampleRolloutRate(duration, interval time.Duration) (float64, error) {
+	if valid, err := builder.validate(); !valid {
+		return 0, err
+	}
+
+	verbose().Infof("Sampling the rollout rate of MachineConfigPool %s every %v for %v",
+		builder.Definition.Name, interval, duration)
+
+	if interval <= 0 || duration < interval {
+		verbose().Infof("The sampling interval must be positive and not longer than the duration")
+
+		return 0, fmt.Errorf("sampling interval %v must be positive and not longer than duration %v", interval, duration)
+	}
+
+	if err := builder.refresh(); err != nil {
+		return 0, err
+	}
+
+	var updatedMachines int32
+
+	lastCount := builder.Object.Status.UpdatedMachineCount
+	startTime := time.Now()
+
+	for time.Since(startTime) < duration {
+		time.Sleep(interval)
+
+		if err := builder.refresh(); err != nil {
+			return 0, err
+		}
+
+		currentCount := builder.Object.Status.UpdatedMachineCount
+		if currentCount > lastCount {
+			updatedMachines += currentCount - lastCount
+		}
+
+		lastCount = currentCount
+	}
+
+	return float64(updatedMachines) / time.Since(startTime).Minutes(), nil
+}
+
+// GetConditionTransitionTime returns the last transition time of the given MachineConfigPool condition type.
+func (builder *MCPBuilder) GetConditionTransitionTime(
+	conditionType mcov1.MachineConfigPoolConditionType) (time.Time, error) {
+	if valid, err := builder.validate(); !valid {
+		return time.Time{}, err
+	}
+
+	verbose().Infof("Getting the last transition time of condition %v of MachineConfigPool %s",
+		conditionType, builder.Definition.Name)
+
+	condition, err := builder.getCondition(conditionType)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return condition.LastTransitionTime.Time, nil
+}
+
+// GetConditionMessage returns the message of the given condition type of the MachineConfigPool, e.g. the
+// degradation reason of the Degraded condition.
+func (builder *MCPBuilder) GetConditionMessage(conditionType mcov1.MachineConfigPoolConditionType) (string, error) {
+	if valid, err := builder.validate(); !valid {
+		return "", err
+	}
+
+	verbose().Infof("Getting the message of condition %v of MachineConfigPool %s",
+		conditionType, builder.Definition.Name)
+
+	condition, err := builder.getCondition(conditionType)
+	if err != nil {
+		return "", err
+	}
+
+	return condition.Message, nil
+}
+
+// GetCurrentOSImageURL returns the OS image URL of the rendered MachineConfig the MachineConfigPool is running.
+func (builder *MCPBuilder) GetCurrentOSImageURL() (string, error) {
+	if valid, err := builder.validate(); !valid {
+		return "", err
+	}
+
+	verbose().Infof("Getting the OS image URL of the MachineConfigPool %s", builder.Definition.Name)
+
+	renderedConfig, err := builder.getRenderedConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return renderedConfig.Object.Spec.OSImageURL, nil
+}
+
+// GetMachineOSConfig returns the builder of the MachineOSConfig targeting the MachineConfigPool, which enables
+// on-cluster image layering for the pool.
+func (builder *MCPBuilder) GetMachineOSConfig() (*MachineOSConfigBuilder, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting the MachineOSConfig of MachineConfigPool %s", builder.Definition.Name)
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	moscList, err := builder.apiClient.Resource(machineOSConfigGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MachineOSConfigs: %w", err)
+	}
+
+	for index := range moscList.Items {
+		moscBuilder := &MachineOSConfigBuilder{
+			apiClient:  builder.apiClient,
+			Definition: &moscList.Items[index],
+			Object:     &moscList.Items[index],
+		}
+
+		poolName, err := moscBuilder.GetMachineConfigPoolName()
+		if err != nil {
+			return nil, err
+		}
+
+		if poolName == builder.Definition.Name {
+			return moscBuilder, nil
+		}
+	}
+
+	verbose().Infof("No MachineOSConfig targets MachineConfigPool %s", builder.Definition.Name)
+
+	return nil, fmt.Errorf("no MachineOSConfig targets MachineConfigPool %s", builder.Definition.Name)
+}
+
+// GetAffectingRuntimeConfigs returns the builders of the ContainerRuntimeConfigs whose machineConfigPoolSelector
+// matches the labels of the MachineConfigPool object.
+func (builder *MCPBuilder) GetAffectingRuntimeConfigs() ([]*ContainerRuntimeConfigBuilder, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting the ContainerRuntimeConfigs affecting MachineConfigPool %s", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	runtimeConfigList, err := builder.apiClient.ContainerRuntimeConfigs().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ContainerRuntimeConfigs: %w", err)
+	}
+
+	var runtimeConfigs []*ContainerRuntimeConfigBuilder
+
+	for index := range runtimeConfigList.Items {
+		runtimeConfig := &runtimeConfigList.Items[index]
+
+		selected, err := builder.isSelectedBy(runtimeConfig.Spec.MachineConfigPoolSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid machineConfigPoolSelector of ContainerRuntimeConfig %s: %w",
+				runtimeConfig.Name, err)
+		}
+
+		if selected {
+			runtimeConfigs = append(runtimeConfigs, &ContainerRuntimeConfigBuilder{
+				apiClient:  builder.apiClient,
+				Definition: runtimeConfig,
+				Object:     runtimeConfig,
+			})
+		}
+	}
+
+	return runtimeConfigs, nil
+}
+
+// GetAffectingKubeletConfigs returns the builders of the KubeletConfigs whose machineConfigPoolSelector matches
+// the labels of the MachineConfigPool object.
+func (builder *MCPBuilder) GetAffectingKubeletConfigs() ([]*KubeletConfigBuilder, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting the KubeletConfigs affecting MachineConfigPool %s", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	kubeletConfigList, err := builder.apiClient.KubeletConfigs().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KubeletConfigs: %w", err)
+	}
+
+	var kubeletConfigs []*KubeletConfigBuilder
+
+	for index := range kubeletConfigList.Items {
+		kubeletConfig := &kubeletConfigList.Items[index]
+
+		selected, err := builder.isSelectedBy(kubeletConfig.Spec.MachineConfigPoolSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid machineConfigPoolSelector of KubeletConfig %s: %w", kubeletConfig.Name, err)
+		}
+
+		if selected {
+			kubeletConfigs = append(kubeletConfigs, &KubeletConfigBuilder{
+				apiClient:  builder.apiClient,
+				Definition: kubeletConfig,
+				Object:     kubeletConfig,
+			})
+		}
+	}
+
+	return kubeletConfigs, nil
+}
+
+// GetLastAppliedConfigs returns the names of the at most limit rendered MachineConfigs the MachineConfigPool ran,
+// as recorded by the MCO: the configuration in the pool status first, followed by the other configurations the
+// nodes of the pool report in their currentConfig annotation, in node name order. Each name is returned once.
+func (builder *MCPBuilder) GetLastAppliedConfigs(limit int) ([]string, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting the last %d applied configs of MachineConfigPool %s", limit, builder.Definition.Name)
+
+	if limit <= 0 {
+		verbose().Infof("The limit must be positive")
+
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(poolNodes, func(i, j int) bool {
+		return poolNodes[i].Name < poolNodes[j].Name
+	})
+
+	appliedConfigs := []string{builder.Object.Status.Configuration.Name}
+
+	for _, node := range poolNodes {
+		appliedConfigs = append(appliedConfigs, node.Annotations[daemonconsts.CurrentMachineConfigAnnotationKey])
+	}
+
+	var configNames []string
+
+	for _, configName := range appliedConfigs {
+		if len(configNames) == limit {
+			break
+		}
+
+		if configName != "" && !slices.Contains(configNames, configName) {
+			configNames = append(configNames, configName)
+		}
+	}
+
+	return configNames, nil
+}
+
+// SelectsMachineConfig returns true if the machineConfigSelector of the MachineConfigPool definition matches the
+// labels of the given MachineConfig.
+func (builder *MCPBuilder) SelectsMachineConfig(machineConfig *mcov1.MachineConfig) (bool, error) {
+	if valid, err := builder.validate(); !valid {
+		return false, err
+	}
+
+	if machineConfig == nil {
+		verbose().Infof("The MachineConfig is nil")
+
+		return false, fmt.Errorf("'machineConfig' cannot be nil")
+	}
+
+	verbose().Infof("Checking if MachineConfigPool %s selects MachineConfig %s",
+		builder.Definition.Name, machineConfig.Name)
+
+	return selectsMachineConfig(&builder.Definition.Spec, machineConfig)
+}
+
+// LabelMachineConfigForPool copies the machineConfigSelector MatchLabels of the MachineConfigPool definition onto
+// the given MachineConfig and updates it on the cluster if it already exists, so that the pool selects it. The update
+// is made against the resourceVersion just fetched, any error of that request other than NotFound is returned.
+func (builder *MCPBuilder) LabelMachineConfigForPool(machineConfig *MCBuilder) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	if valid, err := machineConfig.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Labeling MachineConfig %s for MachineConfigPool %s",
+		machineConfig.Definition.Name, builder.Definition.Name)
+
+	mcSelector := builder.Definition.Spec.MachineConfigSelector
+	if mcSelector == nil || len(mcSelector.MatchLabels) == 0 {
+		verbose().Infof("The MachineConfigPool %s has no machineConfigSelector matchLabels", builder.Definition.Name)
+
+		return fmt.Errorf("MachineConfigPool %s has no machineConfigSelector matchLabels to copy",
+			builder.Definition.Name)
+	}
+
+	if len(mcSelector.MatchExpressions) > 0 {
+		verbose().Infof("The MachineConfigPool %s machineConfigSelector matchExpressions are not copied",
+			builder.Definition.Name)
+	}
+
+	for key, value := range mcSelector.MatchLabels {
+		machineConfig.WithLabel(key, value)
+	}
+
+	exists, err := machineConfig.exists()
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		verbose().Infof("The MachineConfig %s does not exist yet, only its definition is labeled",
+			machineConfig.Definition.Name)
+
+		return nil
+	}
+
+	machineConfig.Definition.ResourceVersion = machineConfig.Object.ResourceVersion
+
+	_, err = machineConfig.Update()
+	if err != nil {
+		return fmt.Errorf("failed to update labels of MachineConfig %s: %w", machineConfig.Definition.Name, err)
+	}
+
+	return nil
+}
+
+// ValidateMachineConfigForPool checks without persisting anything that the given MachineConfig is accepted by the
+// API server, using a server-side dry-run, and that it is selected by the MachineConfigPool definition.
+func (builder *MCPBuilder) ValidateMachineConfigForPool(machineConfig *mcov1.MachineConfig) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	if machineConfig == nil {
+		verbose().Infof("The MachineConfig is nil")
+
+		return fmt.Errorf("'machineConfig' cannot be nil")
+	}
+
+	verbose().Infof("Validating MachineConfig %s for MachineConfigPool %s",
+		machineConfig.Name, builder.Definition.Name)
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	_, err := builder.apiClient.MachineConfigs().Create(
+		ctx, machineConfig, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+
+	if k8serrors.IsAlreadyExists(err) {
+		verbose().Infof("The MachineConfig %s already exists, validating it as an update", machineConfig.Name)
+
+		var existingConfig *mcov1.MachineConfig
+
+		existingConfig, err = builder.apiClient.MachineConfigs().Get(ctx, machineConfig.Name, metav1.GetOptions{})
+		if err == nil {
+			updatedConfig := machineConfig.DeepCopy()
+			updatedConfig.ResourceVersion = existingConfig.ResourceVersion
+
+			_, err = builder.apiClient.MachineConfigs().Update(
+				ctx, updatedConfig, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+		}
+	}
+
+	if err != nil {
+		verbose().Infof("The MachineConfig %s was rejected: %v", machineConfig.Name, err)
+
+		return fmt.Errorf("MachineConfig %s was rejected by the API server: %w", machineConfig.Name, err)
+	}
+
+	selected, err := selectsMachineConfig(&builder.Definition.Spec, machineConfig)
+	if err != nil {
+		return err
+	}
+
+	if !selected {
+		verbose().Infof("The MachineConfig %s is not selected by MachineConfigPool %s",
+			machineConfig.Name, builder.Definition.Name)
+
+		return fmt.Errorf("MachineConfig %s is not selected by MachineConfigPool %s",
+			machineConfig.Name, builder.Definition.Name)
+	}
+
+	return nil
+}
+
+// DiffRenderedConfigs returns a textual diff between the specs of the rendered MachineConfigs of the two
+// given MachineConfigPools. An empty diff means the rendered MachineConfigs have the same spec.
+func DiffRenderedConfigs(poolA, poolB *MCPBuilder) (string, error) {
+	verbose().Infof("Comparing the rendered MachineConfigs of two MachineConfigPools")
+
+	for _, pool := range []*MCPBuilder{poolA, poolB} {
+		if valid, err := pool.validate(); !valid {
+			return "", err
+		}
+	}
+
+	renderedConfigA, err := poolA.getRenderedConfig()
+	if err != nil {
+		return "", err
+	}
+
+	renderedConfigB, err := poolB.getRenderedConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return cmp.Diff(renderedConfigA.Object.Spec, renderedConfigB.Object.Spec), nil
+}
+
+// GetPoolSynchronizersStatus returns the status of the pool synchronizers of the MachineConfigPool object. An empty
+// slice is returned if the MCO of the cluster does not report the field.
+func (builder *MCPBuilder) GetPoolSynchronizersStatus() ([]PoolSynchronizerStatus, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting the pool synchronizers status of MachineConfigPool %s", builder.Definition.Name)
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	// the typed client drops the field unknown to the vendored API, so the object is read through the dynamic client.
+	mcp, err := builder.apiClient.Resource(mcov1.SchemeGroupVersion.WithResource("machineconfigpools")).Get(
+		ctx, builder.Definition.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	synchronizers, found, err := unstructured.NestedSlice(mcp.Object, "status", "poolSynchronizersStatus")
+	if err != nil {
+		return nil, fmt.Errorf("invalid poolSynchronizersStatus of MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	if !found {
+		verbose().Infof("The MachineConfigPool %s does not report poolSynchronizersStatus", builder.Definition.Name)
+
+		return []PoolSynchronizerStatus{}, nil
+	}
+
+	synchronizersJSON, err := json.Marshal(synchronizers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal poolSynchronizersStatus of MachineConfigPool %s: %w",
+			builder.Definition.Name, err)
+	}
+
+	synchronizersStatus := []PoolSynchronizerStatus{}
+
+	err = json.Unmarshal(synchronizersJSON, &synchronizersStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal poolSynchronizersStatus of MachineConfigPool %s: %w",
+			builder.Definition.Name, err)
+	}
+
+	return synchronizersStatus, nil
+}
+
+// RequiresFeatureGate returns true if the given feature gate is enabled on the cluster, e.g. to skip creating a
+// MachineConfigPool that relies on a feature behind the gate. The enabled feature gates reported in the FeatureGate
+// status are used when available. Older clusters that do not report them fall back to the configured feature set.
+func RequiresFeatureGate(apiClient *clients.Settings, gate string) (bool, error) {
+	verbose().Infof("Checking if feature gate %s is enabled", gate)
+
+	if apiClient == nil {
+		verbose().Infof("The apiClient is nil")
+
+		return false, fmt.Errorf("apiClient cannot be nil")
+	}
+
+	if gate == "" {
+		verbose().Infof("The feature gate cannot be empty")
+
+		return false, fmt.Errorf("'gate' cannot be empty")
+	}
+
+	// the vendored FeatureGate API has no status fields, so the object is read through the dynamic client.
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	featureGate, err := apiClient.Resource(configv1.GroupVersion.WithResource("featuregates")).Get(
+		ctx, clusterFeatureGateName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get FeatureGate %s: %w", clusterFeatureGateName, err)
+	}
+
+	statusFeatureGates, found, _ := unstructured.NestedSlice(featureGate.Object, "status", "featureGates")
+	if found && len(statusFeatureGates) > 0 {
+		for _, versionFeatureGates := range statusFeatureGates {
+			versionMap, ok := versionFeatureGates.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			enabledGates, _, _ := unstructured.NestedSlice(versionMap, "enabled")
+			for _, enabledGate := range enabledGates {
+				if gateMap, ok := enabledGate.(map[string]interface{}); ok && gateMap["name"] == gate {
+					return true, nil
+				}
+			}
+		}
+
+		return false, nil
+	}
+
+	featureSet, _, _ := unstructured.NestedString(featureGate.Object, "spec", "featureSet")
+
+	var enabledGates []string
+
+	if configv1.FeatureSet(featureSet) == configv1.CustomNoUpgrade {
+		enabledGates, _, _ = unstructured.NestedStringSlice(featureGate.Object, "spec", "customNoUpgrade", "enabled")
+	} else if featureGates, ok := configv1.FeatureSets[configv1.FeatureSet(featureSet)]; ok {
+		enabledGates = featureGates.Enabled
+	}
+
+	return slices.Contains(enabledGates, gate), nil
+}
+
+// GetNodeConfigStates returns the desired config, current config and machine-config-daemon state of every
+// node of the MachineConfigPool, keyed by node name.
+func (builder *MCPBuilder) GetNodeConfigStates() (map[string]NodeConfigState, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting node config states of the MachineConfigPool %s", builder.Definition.Name)
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeConfigStates := make(map[string]NodeConfigState, len(poolNodes))
+
+	for _, node := range poolNodes {
+		nodeConfigStates[node.Name] = NodeConfigState{
+			DesiredConfig: node.Annotations[daemonconsts.DesiredMachineConfigAnnotationKey],
+			CurrentConfig: node.Annotations[daemonconsts.CurrentMachineConfigAnnotationKey],
+			State:         node.Annotations[daemonconsts.MachineConfigDaemonStateAnnotationKey],
+		}
+	}
+
+	return nodeConfigStates, nil
+}
+
+// GetConfigDriftReport returns the current and desired config of every node of the MachineConfigPool, sorted by
+// node name, flagging the nodes that have not caught up with their desired config yet.
+func (builder *MCPBuilder) GetConfigDriftReport() ([]NodeDrift, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting the config drift report of the MachineConfigPool %s", builder.Definition.Name)
+
+	nodeConfigStates, err := builder.GetNodeConfigStates()
+	if err != nil {
+		return nil, err
+	}
+
+	driftReport := make([]NodeDrift, 0, len(nodeConfigStates))
+
+	for nodeName, nodeConfigState := range nodeConfigStates {
+		driftReport = append(driftReport, NodeDrift{
+			NodeName:      nodeName,
+			CurrentConfig: nodeConfigState.CurrentConfig,
+			DesiredConfig: nodeConfigState.DesiredConfig,
+			Drifted:       nodeConfigState.CurrentConfig != nodeConfigState.DesiredConfig,
+		})
+	}
+
+	sort.Slice(driftReport, func(i, j int) bool {
+		return driftReport[i].NodeName < driftReport[j].NodeName
+	})
+
+	return driftReport, nil
+}
+
+// GetNodeBootIDs returns the boot ID of every node of the MachineConfigPool, keyed by node name, e.g. to be
+// passed to CountNodeRebootsDuringUpdate after an update.
+func (builder *MCPBuilder) GetNodeBootIDs() (map[string]string, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Getting node boot IDs of the MachineConfigPool %s", builder.Definition.Name)
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	bootIDs := make(map[string]string, len(poolNodes))
+
+	for _, node := range poolNodes {
+		bootIDs[node.Name] = node.Status.NodeInfo.BootID
+	}
+
+	return bootIDs, nil
+}
+
+// CountNodeRebootsDuringUpdate returns the number of nodes of the MachineConfigPool whose boot ID differs from the
+// given boot IDs captured before the update, see GetNodeBootIDs. Nodes missing from the given boot IDs are ignored.
+func (builder *MCPBuilder) CountNodeRebootsDuringUpdate(before map[string]string) (int, error) {
+	if valid, err := builder.validate(); !valid {
+		return 0, err
+	}
+
+	verbose().Infof("Counting node reboots of the MachineConfigPool %s", builder.Definition.Name)
+
+	if len(before) == 0 {
+		verbose().Infof("The boot IDs captured before the update cannot be empty")
+
+		return 0, fmt.Errorf("boot IDs captured before the update cannot be empty")
+	}
+
+	currentBootIDs, err := builder.GetNodeBootIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	reboots := 0
+
+	for nodeName, currentBootID := range currentBootIDs {
+		previousBootID, found := before[nodeName]
+		if !found {
+			continue
+		}
+
+		if currentBootID != previousBootID {
+			verbose().Infof("Node %s of MachineConfigPool %s was rebooted", nodeName, builder.Definition.Name)
+
+			reboots++
+		}
+	}
+
+	return reboots, nil
+}
+
+// GetMCDLogsForNode returns the logs of the last hour of the machine-config-daemon pod running on the given node.
+func (builder *MCPBuilder) GetMCDLogsForNode(nodeName string) (string, error) {
+	if valid, err := builder.validate(); !valid {
+		return "", err
+	}
+
+	verbose().Infof("Getting machine-config-daemon logs for node %s", nodeName)
+
+	mcdPod, err := builder.getMCDPodForNode(nodeName)
+	if err != nil {
+		return "", err
+	}
+
+	return mcdPod.GetLog(mcdLogsSince, mcdContainerName)
+}
+
+// AllNodesOnSameConfig returns true and the shared config name if all nodes of the MachineConfigPool report the
+// same current config. A MachineConfigPool without nodes is reported as being on the same, empty, config.
+func (builder *MCPBuilder) AllNodesOnSameConfig() (bool, string, error) {
+	if valid, err := builder.validate(); !valid {
+		return false, "", err
+	}
+
+	verbose().Infof("Checking if all nodes of MachineConfigPool %s are on the same config", builder.Definition.Name)
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return false, "", err
+	}
+
+	if len(poolNodes) == 0 {
+		return true, "", nil
+	}
+
+	sharedConfig := poolNodes[0].Annotations[daemonconsts.CurrentMachineConfigAnnotationKey]
+
+	for _, node := range poolNodes[1:] {
+		currentConfig := node.Annotations[daemonconsts.CurrentMachineConfigAnnotationKey]
+		if currentConfig != sharedConfig {
+			verbose().Infof("Node %s is on config %s while node %s is on config %s",
+				node.Name, currentConfig, poolNodes[0].Name, sharedConfig)
+
+			return false, "", nil
+		}
+	}
+
+	return true, sharedConfig, nil
+}
+
+// Diagnose returns a troubleshooting snapshot of the MachineConfigPool including its conditions, machine counts,
+// generations, degraded nodes and events.
+func (builder *MCPBuilder) Diagnose() (*MCPDiagnosis, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Diagnosing the MachineConfigPool %s", builder.Definition.Name)
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	diagnosis := &MCPDiagnosis{
+		Conditions:              builder.Object.Status.Conditions,
+		MachineCount:            builder.Object.Status.MachineCount,
+		UpdatedMachineCount:     builder.Object.Status.UpdatedMachineCount,
+		ReadyMachineCount:       builder.Object.Status.ReadyMachineCount,
+		UnavailableMachineCount: builder.Object.Status.UnavailableMachineCount,
+		DegradedMachineCount:    builder.Object.Status.DegradedMachineCount,
+		Generation:              builder.Object.Generation,
+		ObservedGeneration:      builder.Object.Status.ObservedGeneration,
+	}
+
+	for _, node := range poolNodes {
+		switch node.Annotations[daemonconsts.MachineConfigDaemonStateAnnotationKey] {
+		case daemonconsts.MachineConfigDaemonStateDegraded, daemonconsts.MachineConfigDaemonStateUnreconcilable:
+			diagnosis.DegradedNodes = append(diagnosis.DegradedNodes, node.Name)
+		}
+	}
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	eventList, err := builder.apiClient.CoreV1Interface.Events("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.kind": machineConfigPool,
+			"involvedObject.name": builder.Definition.Name,
+		}.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events of MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	diagnosis.Events = eventList.Items
+
+	return diagnosis, nil
+}
+
+// ForEachNode calls the given function for every node of the MachineConfigPool. All nodes are visited and the
+// errors returned by the function are aggregated.
+func (builder *MCPBuilder) ForEachNode(nodeFunc func(node *corev1.Node) error) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Running function on each node of MachineConfigPool %s", builder.Definition.Name)
+
+	if nodeFunc == nil {
+		verbose().Infof("The node function cannot be nil")
+
+		return fmt.Errorf("node function cannot be nil")
+	}
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return err
+	}
+
+	var errorMessages []string
+
+	for index := range poolNodes {
+		if err := nodeFunc(&poolNodes[index]); err != nil {
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %v", poolNodes[index].Name, err))
+		}
+	}
+
+	if len(errorMessages) > 0 {
+		return fmt.Errorf("failed on nodes of MachineConfigPool %s: %s",
+			builder.Definition.Name, strings.Join(errorMessages, "; "))
+	}
+
+	return nil
+}
+
+// ForceNodeReconcile clears the machine-config-daemon degraded reason of the given node of the MachineConfigPool
+// and resets its state annotation so that the machine-config-daemon retries to apply the config.
+func (builder *MCPBuilder) ForceNodeReconcile(nodeName string) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Forcing config reconcile of node %s in MachineConfigPool %s", nodeName, builder.Definition.Name)
+
+	if err := builder.validatePoolNode(nodeName); err != nil {
+		return err
+	}
+
+	nodeBuilder, err := nodes.PullNode(builder.apiClient, nodeName)
+	if err != nil {
+		return err
+	}
+
+	delete(nodeBuilder.Definition.Annotations, daemonconsts.MachineConfigDaemonReasonAnnotationKey)
+
+	if nodeBuilder.Definition.Annotations == nil {
+		nodeBuilder.Definition.Annotations = map[string]string{}
+	}
+
+	nodeBuilder.Definition.Annotations[daemonconsts.MachineConfigDaemonStateAnnotationKey] =
+		daemonconsts.MachineConfigDaemonStateDone
+
+	_, err = nodeBuilder.Update()
+	if err != nil {
+		return fmt.Errorf("failed to reset machine-config-daemon annotations of node %s: %w", nodeName, err)
+	}
+
+	return nil
+}
+
+// RestartMCDForNode deletes the machine-config-daemon pod running on the given node of the MachineConfigPool, so
+// that it is recreated by its DaemonSet and retries to apply the config.
+func (builder *MCPBuilder) RestartMCDForNode(nodeName string) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Restarting machine-config-daemon of node %s in MachineConfigPool %s",
+		nodeName, builder.Definition.Name)
+
+	if err := builder.validatePoolNode(nodeName); err != nil {
+		return err
+	}
+
+	mcdPod, err := builder.getMCDPodForNode(nodeName)
+	if err != nil {
+		return err
+	}
+
+	_, err = mcdPod.Delete()
+	if err != nil {
+		return fmt.Errorf("failed to delete machine-config-daemon pod of node %s: %w", nodeName, err)
+	}
+
+	return nil
+}
+
+// VerifyFileOnNode checks that the file at the given absolute path on the given node of the MachineConfigPool has
+// the expected content. The file is read through the machine-config-daemon pod, which mounts the host filesystem.
+func (builder *MCPBuilder) VerifyFileOnNode(nodeName, path, expectedContent string) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Verifying file %s on node %s of MachineConfigPool %s", path, nodeName, builder.Definition.Name)
+
+	if !strings.HasPrefix(path, "/") {
+		verbose().Infof("The file path %s is not absolute", path)
+
+		return fmt.Errorf("file path %s must be absolute", path)
+	}
+
+	if err := builder.validatePoolNode(nodeName); err != nil {
+		return err
+	}
+
+	mcdPod, err := builder.getMCDPodForNode(nodeName)
+	if err != nil {
+		return err
+	}
+
+	output, err := mcdPod.ExecCommand([]string{"cat", mcdHostRoot + path}, mcdContainerName)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s on node %s: %w", path, nodeName, err)
+	}
+
+	if output.String() != expectedContent {
+		verbose().Infof("The file %s on node %s does not have the expected content", path, nodeName)
+
+		return fmt.Errorf("file %s on node %s does not have the expected content", path, nodeName)
+	}
+
+	return nil
+}
+
+// RemoveRoleLabelFromPoolNodes removes the node-role label of the given custom role from every node the
+// MachineConfigPool selects, e.g. to dissolve a custom pool back into the worker pool. Nodes without the label are
+// skipped, and the errors of all nodes that failed to update are aggregated.
+func (builder *MCPBuilder) RemoveRoleLabelFromPoolNodes(role string) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Removing role %s label from nodes of MachineConfigPool %s", role, builder.Definition.Name)
+
+	if role == "" {
+		verbose().Infof("The role cannot be empty")
+
+		return fmt.Errorf("'role' cannot be empty")
+	}
+
+	if isReservedPoolName(role) {
+		verbose().Infof("The role %s is a built-in role", role)
+
+		return fmt.Errorf("role %s is a built-in role and cannot be removed from nodes", role)
+	}
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return err
+	}
+
+	roleLabel := nodeRoleLabelPrefix + role
+
+	var errorMessages []string
+
+	for _, poolNode := range poolNodes {
+		if _, hasLabel := poolNode.Labels[roleLabel]; !hasLabel {
+			continue
+		}
+
+		nodeBuilder, err := nodes.PullNode(builder.apiClient, poolNode.Name)
+		if err == nil {
+			_, err = nodeBuilder.RemoveLabel(roleLabel, "").Update()
+		}
+
+		if err != nil {
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %v", poolNode.Name, err))
+		}
+	}
+
+	if len(errorMessages) > 0 {
+		return fmt.Errorf("failed to remove label %s from nodes: %s", roleLabel, strings.Join(errorMessages, "; "))
+	}
+
+	return nil
+}
+
+// DrainNodesInPool cordons every node of the MachineConfigPool in sequence and evicts its pods using the given
+// termination grace period. Evictions blocked by PodDisruptionBudgets are retried for up to five minutes per pod.
+// DaemonSet and mirror pods are skipped. Errors are aggregated per node.
+func (builder *MCPBuilder) DrainNodesInPool(gracePeriod time.Duration) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Draining nodes of MachineConfigPool %s with grace period %v", builder.Definition.Name, gracePeriod)
+
+	if gracePeriod < 0 {
+		verbose().Infof("The grace period cannot be negative")
+
+		return fmt.Errorf("'gracePeriod' cannot be negative, got %v", gracePeriod)
+	}
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return err
+	}
+
+	var errorMessages []string
+
+	for _, node := range poolNodes {
+		if err := builder.drainNode(node.Name, gracePeriod); err != nil {
+			verbose().Infof("Failed to drain node %s: %v", node.Name, err)
+
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %v", node.Name, err))
+		}
+	}
+
+	if len(errorMessages) > 0 {
+		return fmt.Errorf("failed to drain nodes of MachineConfigPool %s: %s",
+			builder.Definition.Name, strings.Join(errorMessages, "; "))
+	}
+
+	return nil
+}
+
+// drainNode cordons the given node and evicts all its pods but DaemonSet and mirror pods.
+func (builder *MCPBuilder) drainNode(nodeName string, gracePeriod time.Duration) error {
+	nodeBuilder, err := nodes.PullNode(builder.apiClient, nodeName)
+	if err != nil {
+		return err
+	}
+
+	if !nodeBuilder.Definition.Spec.Unschedulable {
+		verbose().Infof("Cordoning node %s", nodeName)
+
+		nodeBuilder.Definition.Spec.Unschedulable = true
+
+		if _, err := nodeBuilder.Update(); err != nil {
+			return fmt.Errorf("failed to cordon node: %w", err)
+		}
+	}
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	podList, err := builder.apiClient.CoreV1Interface.Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	gracePeriodSeconds := int64(gracePeriod.Seconds())
+
+	for _, nodePod := range podList.Items {
+		if isDaemonSetOrMirrorPod(nodePod) || nodePod.Status.Phase == corev1.PodSucceeded ||
+			nodePod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		eviction := &policyv1.Eviction{
+			ObjectMeta:    metav1.ObjectMeta{Name: nodePod.Name, Namespace: nodePod.Namespace},
+			DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriodSeconds},
+		}
+
+		// Evictions violating a PodDisruptionBudget are rejected with TooManyRequests and retried.
+		err = wait.PollImmediate(builder.getPollInterval(), evictionTimeout, func() (bool, error) {
+			evictionCtx, evictionCancel := builder.operationContext()
+			defer evictionCancel()
+
+			err := builder.apiClient.CoreV1Interface.Pods(nodePod.Namespace).EvictV1(evictionCtx, eviction)
+			if k8serrors.IsTooManyRequests(err) {
+				verbose().Infof("Eviction of pod %s/%s is blocked by a PodDisruptionBudget, retrying",
+					nodePod.Namespace, nodePod.Name)
+
+				return false, nil
+			}
+
+			return err == nil || k8serrors.IsNotFound(err), err
+		})
+
+		if err != nil {
+			return fmt.Errorf("failed to evict pod %s/%s: %w", nodePod.Namespace, nodePod.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// isDaemonSetOrMirrorPod returns true if the pod is managed by a DaemonSet or is a static mirror pod,
+// such pods are not evicted on drain.
+func isDaemonSetOrMirrorPod(nodePod corev1.Pod) bool {
+	if _, isMirror := nodePod.Annotations[mirrorPodAnnotation]; isMirror {
+		return true
+	}
+
+	for _, owner := range nodePod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validatePoolNode returns an error if the given node is not selected by the MachineConfigPool.
+func (builder *MCPBuilder) validatePoolNode(nodeName string) error {
+	if nodeName == "" {
+		verbose().Infof("The nodeName cannot be empty")
+
+		return fmt.Errorf("'nodeName' cannot be empty")
+	}
+
+	poolNodes, err := builder.getPoolNodes()
+	if err != nil {
+		return err
+	}
+
+	for _, node := range poolNodes {
+		if node.Name == nodeName {
+			return nil
+		}
+	}
+
+	verbose().Infof("The node %s does not belong to MachineConfigPool %s", nodeName, builder.Definition.Name)
+
+	return fmt.Errorf("node %s does not belong to MachineConfigPool %s", nodeName, builder.Definition.Name)
+}
+
+// getMCDPodForNode returns the machine-config-daemon pod running on the given node.
+func (builder *MCPBuilder) getMCDPodForNode(nodeName string) (*pod.Builder, error) {
+	if nodeName == "" {
+		verbose().Infof("The nodeName cannot be empty")
+
+		return nil, fmt.Errorf("'nodeName' cannot be empty")
+	}
+
+	mcdPods, err := pod.List(builder.apiClient, mcoNamespace, metav1.ListOptions{
+		LabelSelector: mcdLabelSelector,
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list machine-config-daemon pods on node %s: %w", nodeName, err)
+	}
+
+	if len(mcdPods) == 0 {
+		verbose().Infof("No machine-config-daemon pod found on node %s", nodeName)
+
+		return nil, fmt.Errorf("no machine-config-daemon pod found on node %s", nodeName)
+	}
+
+	return mcdPods[0], nil
+}
+
+// SetMaxUnavailable patches only the maxUnavailable field of the existing MachineConfigPool object.
+func (builder *MCPBuilder) SetMaxUnavailable(value intstr.IntOrString) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Setting maxUnavailable of MachineConfigPool %s to %s", builder.Definition.Name, value.String())
+
+	if value.Type == intstr.Int && value.IntVal < 1 {
+		verbose().Infof("The maxUnavailable must be at least 1")
+
+		return fmt.Errorf("'maxUnavailable' must be at least 1, got %d", value.IntVal)
+	}
+
+	if _, err := intstr.GetScaledValueFromIntOrPercent(&value, 100, true); err != nil {
+		verbose().Infof("The maxUnavailable %s is invalid: %v", value.String(), err)
+
+		return fmt.Errorf("'maxUnavailable' %s is invalid: %w", value.String(), err)
+	}
+
+	return builder.patch([]jsonPatchOperation{{Op: "add", Path: "/spec/maxUnavailable", Value: value}})
+}
+
+// WithSerialUpdate patches the maxUnavailable of the existing MachineConfigPool object to 1, so that its nodes are
+// updated one at a time, and returns a function that restores the previous maxUnavailable.
+func (builder *MCPBuilder) WithSerialUpdate() (restore func() error, err error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Setting MachineConfigPool %s to update one node at a time", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	var previousMaxUnavailable *intstr.IntOrString
+
+	if builder.Object.Spec.MaxUnavailable != nil {
+		maxUnavailable := *builder.Object.Spec.MaxUnavailable
+		previousMaxUnavailable = &maxUnavailable
+	}
+
+	err = builder.SetMaxUnavailable(intstr.FromInt(1))
+	if err != nil {
+		return nil, err
+	}
+
+	restore = func() error {
+		verbose().Infof("Restoring maxUnavailable of MachineConfigPool %s", builder.Definition.Name)
+
+		if previousMaxUnavailable == nil {
+			return builder.patch([]jsonPatchOperation{{Op: "remove", Path: "/spec/maxUnavailable"}})
+		}
+
+		return builder.patch([]jsonPatchOperation{
+			{Op: "add", Path: "/spec/maxUnavailable", Value: *previousMaxUnavailable}})
+	}
+
+	return restore, nil
+}
+
+// Unpin removes the rendered MachineConfig name from the spec configuration of the existing MachineConfigPool
+// object, so that the render controller sets the configuration of the pool again.
+func (builder *MCPBuilder) Unpin() error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Unpinning the configuration of MachineConfigPool %s", builder.Definition.Name)
+
+	if err := builder.refresh(); err != nil {
+		return err
+	}
+
+	if builder.Object.Spec.Configuration.Name == "" {
+		verbose().Infof("The MachineConfigPool %s has no configuration set", builder.Definition.Name)
+
+		return nil
+	}
+
+	err := builder.patch([]jsonPatchOperation{{Op: "remove", Path: "/spec/configuration/name"}})
+	if err != nil {
+		return err
+	}
+
+	builder.Definition.Spec.Configuration.Name = ""
+
+	return nil
+}
+
+// AssertNoRolloutWhilePaused pauses the existing MachineConfigPool object, creates or updates the given
+// MachineConfig and verifies that the configuration in the pool status does not change for observeDuration.
+// The paused field of the pool is restored afterwards, which lets the MachineConfig roll out if it was unpaused.
+func (builder *MCPBuilder) AssertNoRolloutWhilePaused(
+	machineConfig *MCBuilder, observeDuration time.Duration) (err error) {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	if valid, err := machineConfig.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Verifying that MachineConfig %s does not roll out on paused MachineConfigPool %s for %v",
+		machineConfig.Definition.Name, builder.Definition.Name, observeDuration)
+
+	if err := builder.refresh(); err != nil {
+		return err
+	}
+
+	wasPaused := builder.Object.Spec.Paused
+	initialConfig := builder.Object.Status.Configuration.Name
+
+	err = builder.patch([]jsonPatchOperation{{Op: "add", Path: "/spec/paused", Value: true}})
+	if err != nil {
+		return fmt.Errorf("failed to pause MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	defer func() {
+		verbose().Infof("Restoring paused field of MachineConfigPool %s to %t", builder.Definition.Name, wasPaused)
+
+		restoreErr := builder.patch([]jsonPatchOperation{{Op: "add", Path: "/spec/paused", Value: wasPaused}})
+		if restoreErr != nil && err == nil {
+			err = fmt.Errorf("failed to restore paused field of MachineConfigPool %s: %w",
+				builder.Definition.Name, restoreErr)
+		}
+	}()
+
+	exists, err := machineConfig.exists()
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		machineConfig.Definition.ResourceVersion = machineConfig.Object.ResourceVersion
+		_, err = machineConfig.Update()
+	} else {
+		_, err = machineConfig.Create()
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to apply MachineConfig %s: %w", machineConfig.Definition.Name, err)
+	}
+
+	err = wait.PollImmediate(builder.getPollInterval(), observeDuration, func() (bool, error) {
+		if err := builder.refresh(); err != nil {
+			verbose().Infof("Failed to refresh MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+			return false, nil
+		}
+
+		if currentConfig := builder.Object.Status.Configuration.Name; currentConfig != initialConfig {
+			return false, fmt.Errorf("MachineConfigPool %s rolled out %s while paused, was running %s",
+				builder.Definition.Name, currentConfig, initialConfig)
+		}
+
+		return false, nil
+	})
+
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return nil
+	}
+
+	return err
+}
+
+// SetNodeSelector patches the nodeSelector of the existing MachineConfigPool object with the given matchLabels,
+// replacing the previous selector, and waits shortly until the machineCount matches the newly selected nodes.
+func (builder *MCPBuilder) SetNodeSelector(selector map[string]string) error {
+	if valid, err := builder.validate(); !valid {
+		return err
+	}
+
+	verbose().Infof("Setting nodeSelector of MachineConfigPool %s to %v", builder.Definition.Name, selector)
+
+	if len(selector) == 0 {
+		verbose().Infof("The nodeSelector cannot be empty")
+
+		return fmt.Errorf("'nodeSelector' cannot be empty")
+	}
+
+	nodeSelector := &metav1.LabelSelector{MatchLabels: selector}
+
+	err := builder.patch([]jsonPatchOperation{{Op: "add", Path: "/spec/nodeSelector", Value: nodeSelector}})
+	if err != nil {
+		return err
+	}
+
+	builder.Definition.Spec.NodeSelector = nodeSelector
+
+	return builder.WaitForMachineCountToMatchNodes(nodeSelectorSettleTimeout)
+}
+
+// GetSpecFingerprint returns a stable hash of the selectors, maxUnavailable and paused fields of the
+// MachineConfigPool definition, so that callers can detect a change of the pool intent without a deep compare.
+func (builder *MCPBuilder) GetSpecFingerprint() (string, error) {
+	if valid, err := builder.validate(); !valid {
+		return "", err
+	}
+
+	verbose().Infof("Getting the spec fingerprint of MachineConfigPool %s", builder.Definition.Name)
+
+	fingerprintFields := struct {
+		MachineConfigSelector *metav1.LabelSelector `json:"machineConfigSelector,omitempty"`
+		NodeSelector          *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+		MaxUnavailable        *intstr.IntOrString   `json:"maxUnavailable,omitempty"`
+		Paused                bool                  `json:"paused"`
+	}{
+		MachineConfigSelector: builder.Definition.Spec.MachineConfigSelector,
+		NodeSelector:          builder.Definition.Spec.NodeSelector,
+		MaxUnavailable:        builder.Definition.Spec.MaxUnavailable,
+		Paused:                builder.Definition.Spec.Paused,
+	}
+
+	// json.Marshal sorts map keys, so the matchLabels are serialized in a stable order.
+	fingerprintJSON, err := json.Marshal(fingerprintFields)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal spec of MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(fingerprintJSON)), nil
+}
+
+// ToYAML returns the MachineConfigPool definition marshaled to YAML, suitable for storing as a declarative
+// manifest. Status, managedFields and the metadata fields populated by the API server are stripped.
+func (builder *MCPBuilder) ToYAML() ([]byte, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Marshaling the MachineConfigPool %s definition to YAML", builder.Definition.Name)
+
+	manifest := builder.Definition.DeepCopy()
+	manifest.ManagedFields = nil
+	manifest.UID = ""
+	manifest.ResourceVersion = ""
+	manifest.Generation = 0
+	manifest.CreationTimestamp = metav1.Time{}
+	manifest.Status = mcov1.MachineConfigPoolStatus{}
+
+	versionedManifest, err := builder.toVersioned(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestYAML, err := yaml.Marshal(versionedManifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal MachineConfigPool %s to YAML: %w", builder.Definition.Name, err)
+	}
+
+	return manifestYAML, nil
+}
+
+// ToUnstructured converts the MachineConfigPool definition to an unstructured object, e.g. to use it with the
+// dynamic client.
+func (builder *MCPBuilder) ToUnstructured() (*unstructured.Unstructured, error) {
+	if valid, err := builder.validate(); !valid {
+		return nil, err
+	}
+
+	verbose().Infof("Converting the MachineConfigPool %s definition to unstructured", builder.Definition.Name)
+
+	versionedDefinition, err := builder.toVersioned(builder.Definition.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(versionedDefinition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert MachineConfigPool %s to unstructured: %w",
+			builder.Definition.Name, err)
+	}
+
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// toVersioned converts the given copy of the MachineConfigPool definition to the API version of the conversion
+// scheme, or sets the machineconfiguration.openshift.io/v1 type meta when no conversion scheme is set.
+func (builder *MCPBuilder) toVersioned(definition *mcov1.MachineConfigPool) (runtime.Object, error) {
+	if builder.conversionScheme == nil {
+		definition.APIVersion = mcov1.SchemeGroupVersion.String()
+		definition.Kind = machineConfigPool
+
+		return definition, nil
+	}
+
+	gvks, _, err := builder.conversionScheme.ObjectKinds(definition)
+	if err != nil {
+		return nil, fmt.Errorf("conversion scheme does not know MachineConfigPool %s: %w", definition.Name, err)
+	}
+
+	versioned, err := builder.conversionScheme.ConvertToVersion(definition, gvks[0].GroupVersion())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert MachineConfigPool %s to %s: %w",
+			definition.Name, gvks[0].GroupVersion(), err)
+	}
+
+	return versioned, nil
+}
+
+// patch applies the given JSON patch operations to the existing MachineConfigPool object.
+func (builder *MCPBuilder) patch(operations []jsonPatchOperation) error {
+	if err := builder.refresh(); err != nil {
+		return fmt.Errorf("MachineConfigPool %s cannot be patched: %w", builder.Definition.Name, err)
+	}
+
+	patchData, err := json.Marshal(operations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch for MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	verbose().Infof("Patching MachineConfigPool %s with %s", builder.Definition.Name, string(patchData))
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	builder.Object, err = builder.apiClient.MachineConfigPools().Patch(
+		ctx, builder.Definition.Name, types.JSONPatchType, patchData, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	return nil
+}
+
+// refresh fetches the MachineConfigPool object from the cluster and returns an error if it is not available,
+// including the error of a failed request.
+func (builder *MCPBuilder) refresh() error {
+	exists, err := builder.exists()
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("MachineConfigPool %s does not exist", builder.Definition.Name)
+	}
+
+	return nil
+}
+
+// getRenderedConfig returns the rendered MachineConfig the MachineConfigPool object is running.
+func (builder *MCPBuilder) getRenderedConfig() (*MCBuilder, error) {
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	renderedConfigName := builder.Object.Status.Configuration.Name
+	if renderedConfigName == "" {
+		verbose().Infof("The MachineConfigPool %s has no rendered MachineConfig", builder.Definition.Name)
+
+		return nil, fmt.Errorf("MachineConfigPool %s has no rendered MachineConfig", builder.Definition.Name)
+	}
+
+	renderedConfig, err := PullMachineConfig(builder.apiClient, renderedConfigName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rendered MachineConfig %s of MachineConfigPool %s: %w",
+			renderedConfigName, builder.Definition.Name, err)
+	}
+
+	return renderedConfig, nil
+}
+
+// isSelectedBy returns true if the given machineConfigPoolSelector matches the labels of the MachineConfigPool
+// object. A nil selector selects no MachineConfigPool.
+func (builder *MCPBuilder) isSelectedBy(poolSelector *metav1.LabelSelector) (bool, error) {
+	if poolSelector == nil {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(poolSelector)
+	if err != nil {
+		return false, err
+	}
+
+	return selector.Matches(labels.Set(builder.Object.Labels)), nil
+}
+
+// getCondition returns the given condition type of the MachineConfigPool object.
+func (builder *MCPBuilder) getCondition(
+	conditionType mcov1.MachineConfigPoolConditionType) (*mcov1.MachineConfigPoolCondition, error) {
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	for index := range builder.Object.Status.Conditions {
+		if builder.Object.Status.Conditions[index].Type == conditionType {
+			return &builder.Object.Status.Conditions[index], nil
+		}
+	}
+
+	verbose().Infof("The MachineConfigPool %s has no condition %v", builder.Definition.Name, conditionType)
+
+	return nil, fmt.Errorf("MachineConfigPool %s has no condition %v", builder.Definition.Name, conditionType)
+}
+
+// getPoolNodes returns the nodes selected by the nodeSelector of the MachineConfigPool object.
+func (builder *MCPBuilder) getPoolNodes() ([]corev1.Node, error) {
+	if err := builder.refresh(); err != nil {
+		return nil, err
+	}
+
+	if builder.Object.Spec.NodeSelector == nil {
+		verbose().Infof("The MachineConfigPool %s has no nodeSelector", builder.Definition.Name)
+
+		return nil, fmt.Errorf("MachineConfigPool %s has no nodeSelector", builder.Definition.Name)
+	}
+
+	nodeSelector, err := metav1.LabelSelectorAsSelector(builder.Object.Spec.NodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nodeSelector of MachineConfigPool %s: %w", builder.Definition.Name, err)
+	}
+
+	ctx, cancel := builder.operationContext()
+	defer cancel()
+
+	nodeList, err := builder.apiClient.CoreV1Interface.Nodes().List(
+		ctx, metav1.ListOptions{LabelSelector: nodeSelector.String()})
+	if err != nil {
+		verbose().Infof("Failed to list nodes of the MachineConfigPool %s", builder.Definition.Name)
+
+		return nil, err
+	}
+
+	return nodeList.Items, nil
+}
+
+// watchUntil watches the MachineConfigPool object, starting from the given resourceVersion if not empty, until
+// check returns true or an error, or until the context is done. It returns false if the watch could not be
+// established or was closed, so the caller can fall back to polling.
+func (builder *MCPBuilder) watchUntil(
+	ctx context.Context, resourceVersion string, check func(event watch.Event) (bool, error)) (bool, error) {
+	watcher, err := builder.apiClient.MachineConfigPools().Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", builder.Definition.Name).String(),
+		ResourceVersion: resourceVersion,
+	})
+	if err != nil {
+		verbose().Infof("Failed to watch MachineConfigPool %s: %v", builder.Definition.Name, err)
+
+		return false, nil
+	}
+
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, wait.ErrWaitTimeout
+		case event, ok := <-watcher.ResultChan():
+			if !ok || event.Type == watch.Error {
+				verbose().Infof("The watch of MachineConfigPool %s was closed", builder.Definition.Name)
+
+				return false, nil
+			}
+
+			done, err := check(event)
+			if err != nil || done {
+				return true, err
+			}
+		}
+	}
+}
+
+// selectsMachineConfig returns true if the machineConfigSelector of the given spec matches the labels of the
+// given MachineConfig. A nil machineConfigSelector matches nothing.
+func selectsMachineConfig(spec *mcov1.MachineConfigPoolSpec, machineConfig *mcov1.MachineConfig) (bool, error) {
+	if spec.MachineConfigSelector == nil {
+		return false, nil
+	}
+
+	mcSelector, err := metav1.LabelSelectorAsSelector(spec.MachineConfigSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid machineConfigSelector: %w", err)
+	}
+
+	return mcSelector.Matches(labels.Set(machineConfig.Labels)), nil
+}
+
+// isInConditionStatus returns true if the given MachineConfigPool has the condition type with the given status.
+func isInConditionStatus(
+	mcp *mcov1.MachineConfigPool,
+	conditionType mcov1.MachineConfigPoolConditionType,
+	conditionStatus corev1.ConditionStatus) bool {
+	for _, condition := range mcp.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == conditionStatus {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isStableStatus returns true if all machines of the MachineConfigPool status but up to maxDegraded degraded ones
+// are ready and updated. With maxDegraded set to 0 the ready, updated and total machine counts must be equal, so
+// that transient over-counts while nodes join or leave the pool are not stable.
+func isStableStatus(status *mcov1.MachineConfigPoolStatus, maxDegraded int32) bool {
+	if maxDegraded == 0 {
+		return status.ReadyMachineCount == status.MachineCount &&
+			status.MachineCount == status.UpdatedMachineCount &&
+			status.DegradedMachineCount == 0
+	}
+
+	healthyMachineCount := status.MachineCount - status.DegradedMachineCount
+
+	return status.DegradedMachineCount <= maxDegraded &&
+		status.ReadyMachineCount >= healthyMachineCount &&
+		status.UpdatedMachineCount >= healthyMachineCount
+}
+
+// isTransientAdmissionError returns true if the given error is a timeout, e.g. of an admission webhook, after which
+// the request may succeed when retried. Validation and other terminal errors are not transient.
+func isTransientAdmissionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if k8serrors.IsTimeout(err) || k8serrors.IsServerTimeout(err) || k8serrors.IsTooManyRequests(err) {
+		return true
+	}
+
+	return k8serrors.IsInternalError(err) && strings.Contains(err.Error(), "failed calling webhook")
+}
+
+// isNodeReady returns true if the given node reports the Ready condition as True.
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// isReservedPoolName returns true if the given name is the name of a built-in MachineConfigPool.
+func isReservedPoolName(name string) bool {
+	return name == masterPoolName || name == workerPoolName
+}
+
+// observe records the metrics of the operation started at start and invokes the observer of the builder, if any.
+func (builder *MCPBuilder) observe(operation string, start time.Time, err *error) {
+	if builder == nil {
+		return
+	}
+
+	duration := time.Since(start)
+
+	recordMetrics(builder, operation, duration, *err)
+
+	if builder.observer != nil {
+		builder.observer(operation, duration, *err)
+	}
+}
+
+// operationContext returns the context of a single API call of the builder, derived from the context set with
+// WithContext or otherwise bounded by operationTimeout.
+func (builder *MCPBuilder) operationContext() (context.Context, context.CancelFunc) {
+	if builder.ctx != nil {
+		return context.WithCancel(builder.ctx)
+	}
+
+	return newOperationContext()
+}
+
+// newOperationContext returns the context of a single API call made without a builder, bounded by
+// operationTimeout.
+func newOperationContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), operationTimeout)
+}
+
+// getPollInterval returns the interval used by the wait methods of the builder.
+func (builder *MCPBuilder) getPollInterval() time.Duration {
+	if builder.pollInterval > 0 {
+		return builder.pollInterval
+	}
+
+	return fiveScds
+}
+
+// validate will check that the builder and builder definition are properly initialized before
+// accessing any member fields.
+func (builder *MCPBuilder) validate() (bool, error) {
+	resourceCRD := "MachineConfigPool"
+
+	if builder == nil {
+		verbose().Infof("The %s builder is uninitialized", resourceCRD)
+
+		return false, fmt.Errorf("error: received nil %s builder", resourceCRD)
+	}
+
+	if builder.Definition == nil {
+		verbose().Infof("The %s is undefined", resourceCRD)
+
+		builder.errorMsg = msg.UndefinedCrdObjectErrString(resourceCRD)
+	}
+
+	if builder.apiClient == nil {
+		verbose().Infof("The %s builder apiclient is nil", resourceCRD)
+
+		builder.errorMsg = fmt.Sprintf("%s builder cannot have nil apiClient", resourceCRD)
+	}
+
+	if builder.errorMsg != "" {
+		verbose().Infof("The %s builder has error message: %s", resourceCRD, builder.errorMsg)
+
+		return false, fmt.Errorf(builder.errorMsg)
+	}
 
 	return true, nil
 }
+
+// parseOpenShiftMinorVersion returns the minor version of an OpenShift 4.y or 4.y.z version string.
+func parseOpenShiftMinorVersion(version string) (int, error) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid OpenShift version %q: expected format 4.y or 4.y.z", version)
+	}
+
+	for _, part := range parts {
+		if _, err := strconv.ParseUint(part, 10, 32); err != nil {
+			return 0, fmt.Errorf("invalid OpenShift version %q: %q is not a number", version, part)
+		}
+	}
+
+	if parts[0] != "4" {
+		return 0, fmt.Errorf("unsupported OpenShift version %q: only OpenShift 4 is supported", version)
+	}
+
+	minor, _ := strconv.Atoi(parts[1])
+
+	return minor, nil
+}
@@ -1,11 +1,11 @@
 package mco
 
 import (
-	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/openshift-kni/eco-goinfra/pkg/clients"
 
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
@@ -27,7 +27,7 @@ type MCPListBuilder struct {
 
 // NewMCPListBuilder method creates new instance of MCPListBuilder.
 func NewMCPListBuilder(apiClient *clients.Settings, mcpLabelSelector ...map[string]string) *MCPListBuilder {
-	glog.V(100).Infof(
+	verbose().Infof(
 		"Initializing new MCPListBuilder structure with the following params: %v", mcpLabelSelector)
 
 	builder := &MCPListBuilder{
@@ -38,7 +38,7 @@ func NewMCPListBuilder(apiClient *clients.Settings, mcpLabelSelector ...map[stri
 		// Serialize selector
 		serialSelector := labels.Set(mcpLabelSelector[0]).String()
 		builder.mcSelector = serialSelector
-		glog.V(100).Infof("NewMCPListBuilder builder.mcSelector is: %v", builder.mcSelector)
+		verbose().Infof("NewMCPListBuilder builder.mcSelector is: %v", builder.mcSelector)
 	}
 
 	return builder
@@ -46,29 +46,32 @@ func NewMCPListBuilder(apiClient *clients.Settings, mcpLabelSelector ...map[stri
 
 // Discover method gets the MachineConfigPools in cluster and stores them in the builder struct.
 func (builder *MCPListBuilder) Discover() error {
-	glog.V(100).Infof("Getting the list of MachineConfigPool objects on this cluster")
+	verbose().Infof("Getting the list of MachineConfigPool objects on this cluster")
 
 	var (
 		mcpList *mcov1.MachineConfigPoolList
 		err     error
 	)
 
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
 	if builder.mcSelector != "" {
 		mcpList, err = builder.apiClient.MachineConfigPools().List(
-			context.TODO(), metav1.ListOptions{LabelSelector: builder.mcSelector})
+			ctx, metav1.ListOptions{LabelSelector: builder.mcSelector})
 	} else {
 		mcpList, err = builder.apiClient.MachineConfigPools().List(
-			context.TODO(), metav1.ListOptions{})
+			ctx, metav1.ListOptions{})
 	}
 
 	if err != nil {
-		glog.V(100).Infof("Error to list MachineConfigPools")
+		verbose().Infof("Error to list MachineConfigPools")
 
 		return err
 	}
 
 	if len(mcpList.Items) < 1 {
-		glog.V(100).Infof("Cluster doesn't have MachineConfigPools installed ")
+		verbose().Infof("Cluster doesn't have MachineConfigPools installed ")
 
 		return fmt.Errorf("MacineConfigPool list is empty")
 	}
@@ -76,7 +79,7 @@ func (builder *MCPListBuilder) Discover() error {
 	builder.ObjectList = mcpList
 
 	for _, mcp := range mcpList.Items {
-		glog.V(100).Infof("builder Discover() MachineConfigPoolList contents: %v", mcp.ObjectMeta.Name)
+		verbose().Infof("builder Discover() MachineConfigPoolList contents: %v", mcp.ObjectMeta.Name)
 	}
 
 	return err
@@ -85,7 +88,7 @@ func (builder *MCPListBuilder) Discover() error {
 // WaitToBeStableFor waits on all MachineConfigPools in a MachineConfigConfigPoolList to be
 // stable for a time duration up to the timeout.
 func (builder *MCPListBuilder) WaitToBeStableFor(stableDuration time.Duration, timeout time.Duration) error {
-	glog.V(100).Infof("WaitForMcpListToBeStableFor waits up to duration of %v for "+
+	verbose().Infof("WaitForMcpListToBeStableFor waits up to duration of %v for "+
 		"MachineConfigPoolList to be stable for %v", timeout, stableDuration)
 
 	isMcpListStable := true
@@ -113,7 +116,7 @@ func (builder *MCPListBuilder) WaitToBeStableFor(stableDuration time.Duration, t
 					mcp.Status.DegradedMachineCount != 0 {
 					isMcpListStable = false
 
-					glog.V(100).Infof("MachineConfigPool: %v degraded and has a mismatch in "+
+					verbose().Infof("MachineConfigPool: %v degraded and has a mismatch in "+
 						"machineCount: %v "+"vs machineCountUpdated: "+"%v vs readyMachineCount: %v and "+
 						"degradedMachineCount is : %v \n", mcp.ObjectMeta.Name,
 						mcp.Status.MachineCount, mcp.Status.UpdatedMachineCount,
@@ -129,14 +132,14 @@ func (builder *MCPListBuilder) WaitToBeStableFor(stableDuration time.Duration, t
 		})
 
 		if isMcpListStable {
-			glog.V(100).Infof("MachineConfigPools were stable during during stableDuration: %v",
+			verbose().Infof("MachineConfigPools were stable during during stableDuration: %v",
 				stableDuration)
 
 			// exit the outer wait.PollImmediate block since the mcps were stable during stableDuration.
 			return true, nil
 		}
 
-		glog.V(100).Infof("MachineConfigPools were not stable during stableDuration: %v, retrying ...",
+		verbose().Infof("MachineConfigPools were not stable during stableDuration: %v, retrying ...",
 			stableDuration)
 
 		// keep iterating in the outer wait.PollImmediate waiting for cluster to be stable.
@@ -145,10 +148,10 @@ func (builder *MCPListBuilder) WaitToBeStableFor(stableDuration time.Duration, t
 	})
 
 	if err == nil {
-		glog.V(100).Infof("Cluster was stable during stableDuration: %v", stableDuration)
+		verbose().Infof("Cluster was stable during stableDuration: %v", stableDuration)
 	} else {
 		// Here err is "timed out waiting for the condition"
-		glog.V(100).Infof("Cluster was Un-stable during stableDuration: %v", stableDuration)
+		verbose().Infof("Cluster was Un-stable during stableDuration: %v", stableDuration)
 	}
 
 	return err
@@ -156,9 +159,12 @@ func (builder *MCPListBuilder) WaitToBeStableFor(stableDuration time.Duration, t
 
 // GetByLabel returns all MachineConfigPools with the specified label.
 func (builder *MCPListBuilder) GetByLabel(mcpLabel string) (mcov1.MachineConfigPool, error) {
-	glog.V(100).Infof("GetByLabel returns all MachineConfigPools with the specified label: %v", mcpLabel)
+	verbose().Infof("GetByLabel returns all MachineConfigPools with the specified label: %v", mcpLabel)
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
 
-	mcpList, err := builder.apiClient.MachineConfigPools().List(context.TODO(), metav1.ListOptions{})
+	mcpList, err := builder.apiClient.MachineConfigPools().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return mcov1.MachineConfigPool{}, err
 	}
@@ -182,3 +188,284 @@ func (builder *MCPListBuilder) GetByLabel(mcpLabel string) (mcov1.MachineConfigP
 	return mcov1.MachineConfigPool{}, fmt.Errorf("cannot find MachineConfigPool"+
 		" that targets machineConfig with label: %s", mcpLabel)
 }
+
+// ListDegradedPools returns the builders of all MachineConfigPools on the cluster with the Degraded condition set
+// to True. The degradation reason of each pool is available through GetConditionMessage.
+func ListDegradedPools(apiClient *clients.Settings) ([]*MCPBuilder, error) {
+	verbose().Infof("Listing degraded MachineConfigPools on this cluster")
+
+	if apiClient == nil {
+		verbose().Infof("The apiClient is nil")
+
+		return nil, fmt.Errorf("apiClient cannot be nil")
+	}
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	mcpList, err := apiClient.MachineConfigPools().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		verbose().Infof("Failed to list MachineConfigPools: %v", err)
+
+		return nil, fmt.Errorf("failed to list MachineConfigPools: %w", err)
+	}
+
+	var degradedPools []*MCPBuilder
+
+	for index := range mcpList.Items {
+		mcp := &mcpList.Items[index]
+
+		if !isInConditionStatus(mcp, mcov1.MachineConfigPoolDegraded, isTrue) {
+			continue
+		}
+
+		verbose().Infof("MachineConfigPool %s is degraded", mcp.Name)
+
+		degradedPools = append(degradedPools, &MCPBuilder{
+			apiClient:         apiClient,
+			Definition:        mcp,
+			Object:            mcp,
+			allowReservedName: true,
+		})
+	}
+
+	return degradedPools, nil
+}
+
+// GetPoolsForMachineConfig returns the MachineConfigPools on the cluster whose machineConfigSelector matches the
+// labels of the given MachineConfig, i.e. the pools that would roll out the MachineConfig.
+func GetPoolsForMachineConfig(
+	apiClient *clients.Settings, machineConfig *mcov1.MachineConfig) ([]*MCPBuilder, error) {
+	if apiClient == nil {
+		verbose().Infof("The apiClient is nil")
+
+		return nil, fmt.Errorf("apiClient cannot be nil")
+	}
+
+	if machineConfig == nil {
+		verbose().Infof("The MachineConfig is nil")
+
+		return nil, fmt.Errorf("machineConfig cannot be nil")
+	}
+
+	verbose().Infof("Getting the MachineConfigPools selecting MachineConfig %s", machineConfig.Name)
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	mcpList, err := apiClient.MachineConfigPools().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		verbose().Infof("Failed to list MachineConfigPools: %v", err)
+
+		return nil, fmt.Errorf("failed to list MachineConfigPools: %w", err)
+	}
+
+	var selectingPools []*MCPBuilder
+
+	for index := range mcpList.Items {
+		mcp := &mcpList.Items[index]
+
+		selected, err := selectsMachineConfig(&mcp.Spec, machineConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate MachineConfigPool %s: %w", mcp.Name, err)
+		}
+
+		if !selected {
+			continue
+		}
+
+		verbose().Infof("MachineConfigPool %s selects MachineConfig %s", mcp.Name, machineConfig.Name)
+
+		selectingPools = append(selectingPools, &MCPBuilder{
+			apiClient:         apiClient,
+			Definition:        mcp,
+			Object:            mcp,
+			allowReservedName: true,
+		})
+	}
+
+	return selectingPools, nil
+}
+
+// GetRenderedConfigsByRole returns the name of the rendered MachineConfig each MachineConfigPool on the cluster is
+// running, keyed by pool name, which is also the node role the pool manages.
+func GetRenderedConfigsByRole(apiClient *clients.Settings) (map[string]string, error) {
+	verbose().Infof("Getting the rendered MachineConfigs of all MachineConfigPools on this cluster")
+
+	if apiClient == nil {
+		verbose().Infof("The apiClient is nil")
+
+		return nil, fmt.Errorf("apiClient cannot be nil")
+	}
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	mcpList, err := apiClient.MachineConfigPools().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		verbose().Infof("Failed to list MachineConfigPools: %v", err)
+
+		return nil, fmt.Errorf("failed to list MachineConfigPools: %w", err)
+	}
+
+	renderedConfigs := make(map[string]string, len(mcpList.Items))
+
+	for _, mcp := range mcpList.Items {
+		renderedConfigs[mcp.Name] = mcp.Status.Configuration.Name
+	}
+
+	return renderedConfigs, nil
+}
+
+// DeleteAllCustomPools deletes all custom MachineConfigPools on the cluster, as reported by IsCustomPool, without
+// waiting for their removal. The errors of all MachineConfigPools that failed are aggregated in an MCPAggregateError.
+func DeleteAllCustomPools(apiClient *clients.Settings) error {
+	verbose().Infof("Deleting all custom MachineConfigPools on this cluster")
+
+	return deleteAllCustomPools(apiClient, 0)
+}
+
+// DeleteAllCustomPoolsAndWait deletes all custom MachineConfigPools on the cluster, as reported by IsCustomPool, and
+// waits up to the timeout for every deleted MachineConfigPool to be removed. The errors of all MachineConfigPools
+// that failed are aggregated in an MCPAggregateError.
+func DeleteAllCustomPoolsAndWait(apiClient *clients.Settings, timeout time.Duration) error {
+	verbose().Infof("Deleting all custom MachineConfigPools on this cluster and waiting up to %v for their removal",
+		timeout)
+
+	if timeout <= 0 {
+		verbose().Infof("The timeout must be positive")
+
+		return fmt.Errorf("timeout must be positive, got %v", timeout)
+	}
+
+	return deleteAllCustomPools(apiClient, timeout)
+}
+
+// deleteAllCustomPools deletes all custom MachineConfigPools on the cluster and, with a positive timeout, waits up to
+// the timeout for each of them to be removed.
+func deleteAllCustomPools(apiClient *clients.Settings, timeout time.Duration) error {
+	if apiClient == nil {
+		verbose().Infof("The apiClient is nil")
+
+		return fmt.Errorf("apiClient cannot be nil")
+	}
+
+	ctx, cancel := newOperationContext()
+	defer cancel()
+
+	mcpList, err := apiClient.MachineConfigPools().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		verbose().Infof("Failed to list MachineConfigPools: %v", err)
+
+		return fmt.Errorf("failed to list MachineConfigPools: %w", err)
+	}
+
+	aggregateError := &MCPAggregateError{Operation: "delete custom MachineConfigPools"}
+
+	for index := range mcpList.Items {
+		mcp := &mcpList.Items[index]
+
+		poolBuilder := &MCPBuilder{apiClient: apiClient, Definition: mcp, Object: mcp}
+
+		if !poolBuilder.IsCustomPool() {
+			verbose().Infof("Skipping built-in MachineConfigPool %s", mcp.Name)
+
+			continue
+		}
+
+		verbose().Infof("Deleting custom MachineConfigPool %s", mcp.Name)
+
+		err := poolBuilder.Delete()
+		if err == nil && timeout > 0 {
+			err = poolBuilder.WaitForDeletionWatch(timeout)
+		}
+
+		if err != nil {
+			aggregateError.Errors = append(aggregateError.Errors, MCPPoolError{Pool: mcp.Name, Err: err})
+		}
+	}
+
+	if len(aggregateError.Errors) > 0 {
+		return aggregateError
+	}
+
+	return nil
+}
+
+// FilterByName returns the builders of the given MachineConfigPools whose name is one of the given names,
+// preserving their order. Uninitialized builders are skipped.
+func FilterByName(pools []*MCPBuilder, names ...string) []*MCPBuilder {
+	wantedNames := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		wantedNames[name] = true
+	}
+
+	var filteredPools []*MCPBuilder
+
+	for _, pool := range pools {
+		if pool == nil || pool.Definition == nil {
+			continue
+		}
+
+		if wantedNames[pool.Definition.Name] {
+			filteredPools = append(filteredPools, pool)
+		}
+	}
+
+	return filteredPools
+}
+
+// WaitForNoDegradedPools waits for a specific time duration until no MachineConfigPool on the cluster reports the
+// Degraded condition as True. On timeout the error lists the pools still degraded with the reason of each one.
+func WaitForNoDegradedPools(apiClient *clients.Settings, timeout time.Duration) error {
+	if apiClient == nil {
+		verbose().Infof("The apiClient is nil")
+
+		return fmt.Errorf("apiClient cannot be nil")
+	}
+
+	verbose().Infof("WaitForNoDegradedPools waits up to specified time %v until no MachineConfigPool is degraded",
+		timeout)
+
+	var degradedPools []string
+
+	err := wait.PollImmediate(fiveScds, timeout, func() (bool, error) {
+		ctx, cancel := newOperationContext()
+		defer cancel()
+
+		mcpList, err := apiClient.MachineConfigPools().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			verbose().Infof("Failed to list MachineConfigPools: %v", err)
+
+			return false, nil
+		}
+
+		degradedPools = nil
+
+		for index := range mcpList.Items {
+			for _, condition := range mcpList.Items[index].Status.Conditions {
+				if condition.Type != mcov1.MachineConfigPoolDegraded || condition.Status != isTrue {
+					continue
+				}
+
+				degradedPools = append(degradedPools, fmt.Sprintf("%s (%s: %s)",
+					mcpList.Items[index].Name, condition.Reason, condition.Message))
+			}
+		}
+
+		return len(degradedPools) == 0, nil
+	})
+
+	if err != nil && len(degradedPools) == 0 {
+		return fmt.Errorf("failed to verify that no MachineConfigPool is degraded: %w", err)
+	}
+
+	if err != nil {
+		sort.Strings(degradedPools)
+
+		return fmt.Errorf("MachineConfigPools still degraded: %s: %w", strings.Join(degradedPools, ", "), err)
+	}
+
+	return nil
+}
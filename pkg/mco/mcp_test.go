@@ -0,0 +1,1629 @@
+package mco
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"golang.org/x/exp/slices"
+
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	daemonconsts "github.com/openshift/machine-config-operator/pkg/daemon/constants"
+	//nolint:lll // the import path alone exceeds the line length limit.
+	mcov1client "github.com/openshift/machine-config-operator/pkg/generated/clientset/versioned/typed/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const testPoolName = "test-pool"
+
+// testNodeRoleLabel is the role label of the nodes selected by newTestPoolWithNodeSelector.
+const testNodeRoleLabel = "node-role.kubernetes.io/" + testPoolName
+
+// fakeMCPClient is an in-memory MachineConfigPool client. Methods that are not overridden panic when called.
+type fakeMCPClient struct {
+	mcov1client.MachineConfigPoolInterface
+	mutex sync.Mutex
+	pools map[string]*mcov1.MachineConfigPool
+	// getErr is returned by every Get after the first getErrAfter calls when set.
+	getErr      error
+	getErrAfter int
+	getCalls    int
+	// getReactors are applied to the stored pools right before the Get call of the same number is served.
+	getReactors map[int]func(pools map[string]*mcov1.MachineConfigPool)
+	// stall makes every call block until its context is done.
+	stall bool
+	// watcher is returned by Watch. Watch fails when it is nil.
+	watcher *watch.FakeWatcher
+}
+
+func (client *fakeMCPClient) Get(
+	ctx context.Context, name string, _ metav1.GetOptions) (*mcov1.MachineConfigPool, error) {
+	if client.stall {
+		<-ctx.Done()
+
+		return &mcov1.MachineConfigPool{}, ctx.Err()
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	client.getCalls++
+
+	if reactor, found := client.getReactors[client.getCalls]; found {
+		reactor(client.pools)
+	}
+
+	if client.getErr != nil && client.getCalls > client.getErrAfter {
+		return &mcov1.MachineConfigPool{}, client.getErr
+	}
+
+	mcp, found := client.pools[name]
+	if !found {
+		return &mcov1.MachineConfigPool{}, k8serrors.NewNotFound(mcov1.Resource("machineconfigpools"), name)
+	}
+
+	return mcp.DeepCopy(), nil
+}
+
+func (client *fakeMCPClient) Create(
+	ctx context.Context, mcp *mcov1.MachineConfigPool, _ metav1.CreateOptions) (*mcov1.MachineConfigPool, error) {
+	if client.stall {
+		<-ctx.Done()
+
+		return &mcov1.MachineConfigPool{}, ctx.Err()
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if _, found := client.pools[mcp.Name]; found {
+		return &mcov1.MachineConfigPool{}, k8serrors.NewAlreadyExists(mcov1.Resource("machineconfigpools"), mcp.Name)
+	}
+
+	client.pools[mcp.Name] = mcp.DeepCopy()
+
+	return mcp.DeepCopy(), nil
+}
+
+func (client *fakeMCPClient) Update(
+	ctx context.Context, mcp *mcov1.MachineConfigPool, _ metav1.UpdateOptions) (*mcov1.MachineConfigPool, error) {
+	if client.stall {
+		<-ctx.Done()
+
+		return &mcov1.MachineConfigPool{}, ctx.Err()
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	existing, found := client.pools[mcp.Name]
+	if !found {
+		return &mcov1.MachineConfigPool{}, k8serrors.NewNotFound(mcov1.Resource("machineconfigpools"), mcp.Name)
+	}
+
+	if mcp.ResourceVersion != "" && mcp.ResourceVersion != existing.ResourceVersion {
+		return &mcov1.MachineConfigPool{}, k8serrors.NewConflict(
+			mcov1.Resource("machineconfigpools"), mcp.Name, errors.New("the object has been modified"))
+	}
+
+	updated := mcp.DeepCopy()
+	updated.ResourceVersion = existing.ResourceVersion + "1"
+	client.pools[mcp.Name] = updated
+
+	return updated.DeepCopy(), nil
+}
+
+// Patch applies the add and replace operations of a JSON patch, the only ones sent by the builder.
+func (client *fakeMCPClient) Patch(ctx context.Context, name string, patchType types.PatchType, data []byte,
+	_ metav1.PatchOptions, _ ...string) (*mcov1.MachineConfigPool, error) {
+	if client.stall {
+		<-ctx.Done()
+
+		return &mcov1.MachineConfigPool{}, ctx.Err()
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	existing, found := client.pools[name]
+	if !found {
+		return &mcov1.MachineConfigPool{}, k8serrors.NewNotFound(mcov1.Resource("machineconfigpools"), name)
+	}
+
+	if patchType != types.JSONPatchType {
+		return &mcov1.MachineConfigPool{}, fmt.Errorf("unsupported patch type %s", patchType)
+	}
+
+	var operations []jsonPatchOperation
+
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return &mcov1.MachineConfigPool{}, err
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+	if err != nil {
+		return &mcov1.MachineConfigPool{}, err
+	}
+
+	for _, operation := range operations {
+		path := strings.Split(strings.TrimPrefix(operation.Path, "/"), "/")
+
+		value, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&struct {
+			Value interface{} `json:"value"`
+		}{Value: operation.Value})
+		if err != nil {
+			return &mcov1.MachineConfigPool{}, err
+		}
+
+		if err := unstructured.SetNestedField(object, value["value"], path...); err != nil {
+			return &mcov1.MachineConfigPool{}, err
+		}
+	}
+
+	patched := &mcov1.MachineConfigPool{}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, patched); err != nil {
+		return &mcov1.MachineConfigPool{}, err
+	}
+
+	client.pools[name] = patched
+
+	return patched.DeepCopy(), nil
+}
+
+func (client *fakeMCPClient) Delete(ctx context.Context, name string, _ metav1.DeleteOptions) error {
+	if client.stall {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if _, found := client.pools[name]; !found {
+		return k8serrors.NewNotFound(mcov1.Resource("machineconfigpools"), name)
+	}
+
+	delete(client.pools, name)
+
+	return nil
+}
+
+func (client *fakeMCPClient) List(ctx context.Context, _ metav1.ListOptions) (*mcov1.MachineConfigPoolList, error) {
+	if client.stall {
+		<-ctx.Done()
+
+		return &mcov1.MachineConfigPoolList{}, ctx.Err()
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	mcpList := &mcov1.MachineConfigPoolList{}
+
+	for _, mcp := range client.pools {
+		mcpList.Items = append(mcpList.Items, *mcp.DeepCopy())
+	}
+
+	sort.Slice(mcpList.Items, func(i, j int) bool {
+		return mcpList.Items[i].Name < mcpList.Items[j].Name
+	})
+
+	return mcpList, nil
+}
+
+func (client *fakeMCPClient) Watch(context.Context, metav1.ListOptions) (watch.Interface, error) {
+	if client.watcher == nil {
+		return nil, errors.New("watch is not supported")
+	}
+
+	return client.watcher, nil
+}
+
+// deletePool removes the MachineConfigPool with the given name.
+func (client *fakeMCPClient) deletePool(name string) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	delete(client.pools, name)
+}
+
+// reactOnGet applies the reactor to the stored pools right before the n-th Get from now is served. It lets a test
+// change a pool on a known poll of a wait method instead of racing it with a timer.
+func (client *fakeMCPClient) reactOnGet(n int, reactor func(pools map[string]*mcov1.MachineConfigPool)) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.getReactors == nil {
+		client.getReactors = make(map[int]func(pools map[string]*mcov1.MachineConfigPool))
+	}
+
+	client.getReactors[client.getCalls+n] = reactor
+}
+
+// updatePoolOnGet mutates the MachineConfigPool named testPoolName right before the n-th Get from now is served.
+func (client *fakeMCPClient) updatePoolOnGet(n int, mutate func(mcp *mcov1.MachineConfigPool)) {
+	client.reactOnGet(n, func(pools map[string]*mcov1.MachineConfigPool) {
+		mutate(pools[testPoolName])
+	})
+}
+
+// fakeMCClient is an in-memory MachineConfig client supporting Get, Create and Update only.
+type fakeMCClient struct {
+	mcov1client.MachineConfigInterface
+	mutex   sync.Mutex
+	configs map[string]*mcov1.MachineConfig
+	// getErr is returned by every Get when set.
+	getErr error
+	// writes counts the Create and Update calls.
+	writes int
+}
+
+func (client *fakeMCClient) Get(_ context.Context, name string, _ metav1.GetOptions) (*mcov1.MachineConfig, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.getErr != nil {
+		return &mcov1.MachineConfig{}, client.getErr
+	}
+
+	machineConfig, found := client.configs[name]
+	if !found {
+		return &mcov1.MachineConfig{}, k8serrors.NewNotFound(mcov1.Resource("machineconfigs"), name)
+	}
+
+	return machineConfig.DeepCopy(), nil
+}
+
+func (client *fakeMCClient) Create(
+	_ context.Context, machineConfig *mcov1.MachineConfig, _ metav1.CreateOptions) (*mcov1.MachineConfig, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	client.writes++
+
+	if _, found := client.configs[machineConfig.Name]; found {
+		return &mcov1.MachineConfig{}, k8serrors.NewAlreadyExists(
+			mcov1.Resource("machineconfigs"), machineConfig.Name)
+	}
+
+	client.configs[machineConfig.Name] = machineConfig.DeepCopy()
+
+	return machineConfig.DeepCopy(), nil
+}
+
+func (client *fakeMCClient) Update(
+	_ context.Context, machineConfig *mcov1.MachineConfig, _ metav1.UpdateOptions) (*mcov1.MachineConfig, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	client.writes++
+
+	existing, found := client.configs[machineConfig.Name]
+	if !found {
+		return &mcov1.MachineConfig{}, k8serrors.NewNotFound(mcov1.Resource("machineconfigs"), machineConfig.Name)
+	}
+
+	if machineConfig.ResourceVersion != existing.ResourceVersion {
+		return &mcov1.MachineConfig{}, k8serrors.NewConflict(
+			mcov1.Resource("machineconfigs"), machineConfig.Name, errors.New("the object has been modified"))
+	}
+
+	updated := machineConfig.DeepCopy()
+	updated.ResourceVersion = existing.ResourceVersion + "1"
+	client.configs[machineConfig.Name] = updated
+
+	return updated.DeepCopy(), nil
+}
+
+// fakeMCOClient returns the fakeMCPClient as its MachineConfigPool client and the fakeMCClient, if set, as its
+// MachineConfig client.
+type fakeMCOClient struct {
+	mcov1client.MachineconfigurationV1Interface
+	mcpClient *fakeMCPClient
+	mcClient  *fakeMCClient
+}
+
+func (client *fakeMCOClient) MachineConfigPools() mcov1client.MachineConfigPoolInterface {
+	return client.mcpClient
+}
+
+func (client *fakeMCOClient) MachineConfigs() mcov1client.MachineConfigInterface {
+	return client.mcClient
+}
+
+// fakeNodeClient is an in-memory Node client supporting List only.
+type fakeNodeClient struct {
+	corev1client.NodeInterface
+	nodes []corev1.Node
+}
+
+func (client *fakeNodeClient) List(_ context.Context, options metav1.ListOptions) (*corev1.NodeList, error) {
+	selector, err := labels.Parse(options.LabelSelector)
+	if err != nil {
+		return &corev1.NodeList{}, err
+	}
+
+	nodeList := &corev1.NodeList{}
+
+	for _, node := range client.nodes {
+		if selector.Matches(labels.Set(node.Labels)) {
+			nodeList.Items = append(nodeList.Items, *node.DeepCopy())
+		}
+	}
+
+	return nodeList, nil
+}
+
+// fakeCoreV1Client returns the fakeNodeClient as its Node client.
+type fakeCoreV1Client struct {
+	corev1client.CoreV1Interface
+	nodeClient *fakeNodeClient
+}
+
+func (client *fakeCoreV1Client) Nodes() corev1client.NodeInterface {
+	return client.nodeClient
+}
+
+// withFakeNodes sets the Node client of the given apiClient to a fakeNodeClient holding the given nodes.
+func withFakeNodes(apiClient *clients.Settings, nodes ...corev1.Node) {
+	apiClient.CoreV1Interface = &fakeCoreV1Client{nodeClient: &fakeNodeClient{nodes: nodes}}
+}
+
+// withFakeMachineConfigs sets the MachineConfig client of the given apiClient, as returned by newFakeAPIClient, to a
+// fakeMCClient holding the given MachineConfigs and returns it.
+func withFakeMachineConfigs(apiClient *clients.Settings, machineConfigs ...*mcov1.MachineConfig) *fakeMCClient {
+	mcClient := &fakeMCClient{configs: make(map[string]*mcov1.MachineConfig)}
+
+	for _, machineConfig := range machineConfigs {
+		mcClient.configs[machineConfig.Name] = machineConfig.DeepCopy()
+	}
+
+	apiClient.MachineconfigurationV1Interface.(*fakeMCOClient).mcClient = mcClient
+
+	return mcClient
+}
+
+// newTestNode returns a node with the test role label and the given Ready status and annotations.
+func newTestNode(name string, ready corev1.ConditionStatus, annotations map[string]string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{testNodeRoleLabel: ""},
+			Annotations: annotations,
+		},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}}},
+	}
+}
+
+// newTestPoolWithNodeSelector returns a MachineConfigPool named testPoolName selecting the nodes of newTestNode.
+func newTestPoolWithNodeSelector(machineCount int32) *mcov1.MachineConfigPool {
+	mcp := newTestPool(mcov1.MachineConfigPoolUpdated)
+	mcp.Spec.NodeSelector = &metav1.LabelSelector{MatchLabels: map[string]string{testNodeRoleLabel: ""}}
+	mcp.Status.MachineCount = machineCount
+
+	return mcp
+}
+
+// newFakeAPIClient returns an apiClient backed by a fakeMCPClient holding the given MachineConfigPools.
+func newFakeAPIClient(pools ...*mcov1.MachineConfigPool) (*clients.Settings, *fakeMCPClient) {
+	mcpClient := &fakeMCPClient{pools: make(map[string]*mcov1.MachineConfigPool)}
+
+	for _, mcp := range pools {
+		mcpClient.pools[mcp.Name] = mcp.DeepCopy()
+	}
+
+	return &clients.Settings{MachineconfigurationV1Interface: &fakeMCOClient{mcpClient: mcpClient}}, mcpClient
+}
+
+func TestMCPBuilderCreateStalledClient(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient()
+	mcpClient.stall = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithMcSelector(map[string]string{"role": "test"}).WithContext(ctx)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := builder.Create()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a deadline exceeded error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Create did not return on a stalled client")
+	}
+
+	if builder.Object != nil {
+		t.Errorf("expected no object after a failed Create, got %v", builder.Object)
+	}
+
+	defaultOperationTimeout := operationTimeout
+	operationTimeout = 100 * time.Millisecond
+
+	defer func() { operationTimeout = defaultOperationTimeout }()
+
+	listBuilder := NewMCPListBuilder(apiClient)
+
+	listCalls := map[string]func() error{
+		"Discover": listBuilder.Discover,
+		"GetByLabel": func() error {
+			_, err := listBuilder.GetByLabel("test")
+
+			return err
+		},
+	}
+
+	for name, listCall := range listCalls {
+		listDone := make(chan error, 1)
+
+		go func(listCall func() error) {
+			listDone <- listCall()
+		}(listCall)
+
+		select {
+		case err := <-listDone:
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("%s: expected a deadline exceeded error, got %v", name, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("%s did not return on a stalled client", name)
+		}
+	}
+}
+
+func TestMCPBuilderCreateStalledClientDefaultTimeout(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient()
+	mcpClient.stall = true
+
+	defaultOperationTimeout := operationTimeout
+	operationTimeout = 100 * time.Millisecond
+
+	defer func() { operationTimeout = defaultOperationTimeout }()
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithMcSelector(map[string]string{"role": "test"})
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := builder.Create()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a deadline exceeded error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Create did not return on a stalled client")
+	}
+}
+
+func TestMCPBuilderExistsRequestError(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(&mcov1.MachineConfigPool{ObjectMeta: metav1.ObjectMeta{Name: testPoolName}})
+	mcpClient.getErr = k8serrors.NewServerTimeout(mcov1.Resource("machineconfigpools"), "get", 1)
+
+	builder := NewMCPBuilder(apiClient, testPoolName)
+
+	if builder.Exists() {
+		t.Error("expected Exists to be false on a failed request")
+	}
+
+	if _, err := builder.WithMcSelector(map[string]string{"role": "test"}).Create(); err == nil {
+		t.Error("expected Create to return the request error")
+	}
+}
+
+// getterErrors calls every getter of the builder and returns the error of each one by getter name.
+func getterErrors(builder *MCPBuilder) map[string]error {
+	getterErrs := make(map[string]error)
+
+	_, getterErrs["GetMaxUnavailable"] = builder.GetMaxUnavailable()
+	_, getterErrs["GetMcSelectorFull"] = builder.GetMcSelectorFull()
+	_, getterErrs["GetEffectiveMaxUnavailable"] = builder.GetEffectiveMaxUnavailable()
+	_, getterErrs["GetStatus"] = builder.GetStatus()
+	_, getterErrs["GetConditionsMap"] = builder.GetConditionsMap()
+	_, getterErrs["IsPaused"] = builder.IsPaused()
+	_, getterErrs["GetRemainingNodes"] = builder.GetRemainingNodes()
+	_, getterErrs["GetReadyPercentage"] = builder.GetReadyPercentage()
+	_, getterErrs["GetConditionTransitionTime"] = builder.GetConditionTransitionTime(mcov1.MachineConfigPoolUpdated)
+	_, getterErrs["GetConditionMessage"] = builder.GetConditionMessage(mcov1.MachineConfigPoolUpdated)
+	_, getterErrs["GetCurrentOSImageURL"] = builder.GetCurrentOSImageURL()
+
+	return getterErrs
+}
+
+func TestMCPBuilderGettersZeroValue(t *testing.T) {
+	builder := &MCPBuilder{}
+
+	getterErrs := getterErrors(builder)
+
+	_, getterErrs["GetMachineOSConfig"] = builder.GetMachineOSConfig()
+	_, getterErrs["GetAffectingRuntimeConfigs"] = builder.GetAffectingRuntimeConfigs()
+	_, getterErrs["GetAffectingKubeletConfigs"] = builder.GetAffectingKubeletConfigs()
+	_, getterErrs["GetLastAppliedConfigs"] = builder.GetLastAppliedConfigs(1)
+	_, getterErrs["GetPoolSynchronizersStatus"] = builder.GetPoolSynchronizersStatus()
+	_, getterErrs["GetNodeConfigStates"] = builder.GetNodeConfigStates()
+	_, getterErrs["GetConfigDriftReport"] = builder.GetConfigDriftReport()
+	_, getterErrs["GetNodeBootIDs"] = builder.GetNodeBootIDs()
+	_, getterErrs["GetMCDLogsForNode"] = builder.GetMCDLogsForNode("node")
+	_, _, getterErrs["AllNodesOnSameConfig"] = builder.AllNodesOnSameConfig()
+	_, getterErrs["Diagnose"] = builder.Diagnose()
+	_, getterErrs["GetSpecFingerprint"] = builder.GetSpecFingerprint()
+	_, getterErrs["ToYAML"] = builder.ToYAML()
+	_, getterErrs["ToUnstructured"] = builder.ToUnstructured()
+
+	for getter, err := range getterErrs {
+		if err == nil {
+			t.Errorf("expected %s to return an error on a zero value builder", getter)
+		}
+	}
+
+	if builder.IsInCondition(mcov1.MachineConfigPoolUpdated) {
+		t.Error("expected IsInCondition to be false on a zero value builder")
+	}
+
+	if builder.IsCustomPool() {
+		t.Error("expected IsCustomPool to be false on a zero value builder")
+	}
+}
+
+func TestMCPBuilderGettersRequestError(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(&mcov1.MachineConfigPool{
+		ObjectMeta: metav1.ObjectMeta{Name: testPoolName},
+		Status:     mcov1.MachineConfigPoolStatus{MachineCount: 3},
+	})
+	mcpClient.getErr = k8serrors.NewForbidden(mcov1.Resource("machineconfigpools"), testPoolName, errors.New("denied"))
+
+	builder := NewMCPBuilder(apiClient, testPoolName)
+
+	for getter, err := range getterErrors(builder) {
+		if !k8serrors.IsForbidden(err) {
+			t.Errorf("expected %s to return the forbidden error, got %v", getter, err)
+		}
+	}
+
+	if builder.IsInCondition(mcov1.MachineConfigPoolUpdated) {
+		t.Error("expected IsInCondition to be false on a failed request")
+	}
+}
+
+// newTestPool returns a MachineConfigPool named testPoolName with the given conditions set to True.
+func newTestPool(conditionTypes ...mcov1.MachineConfigPoolConditionType) *mcov1.MachineConfigPool {
+	mcp := &mcov1.MachineConfigPool{ObjectMeta: metav1.ObjectMeta{Name: testPoolName}}
+
+	for _, conditionType := range conditionTypes {
+		mcp.Status.Conditions = append(mcp.Status.Conditions, mcov1.MachineConfigPoolCondition{
+			Type:   conditionType,
+			Status: corev1.ConditionTrue,
+		})
+	}
+
+	return mcp
+}
+
+func TestMCPBuilderWaitToBeInConditionWithoutPull(t *testing.T) {
+	apiClient, _ := newFakeAPIClient(newTestPool(mcov1.MachineConfigPoolUpdated))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	err := builder.WaitToBeInCondition(mcov1.MachineConfigPoolUpdated, corev1.ConditionTrue, time.Second)
+	if err != nil {
+		t.Fatalf("expected the condition to be met, got %v", err)
+	}
+
+	if builder.Object == nil || builder.Object.Name != testPoolName {
+		t.Errorf("expected WaitToBeInCondition to populate the object, got %v", builder.Object)
+	}
+}
+
+func TestMCPBuilderWaitForUpdateWithoutPull(t *testing.T) {
+	apiClient, _ := newFakeAPIClient(newTestPool(mcov1.MachineConfigPoolUpdating, mcov1.MachineConfigPoolUpdated))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForUpdate(time.Second); err != nil {
+		t.Fatalf("expected the pool to be updated, got %v", err)
+	}
+
+	if err := NewMCPBuilder(apiClient, "missing").WaitForUpdate(time.Second); err == nil {
+		t.Error("expected WaitForUpdate to fail for a missing pool")
+	}
+}
+
+func TestWaitForUpdateMultipleInvalidPools(t *testing.T) {
+	apiClient, _ := newFakeAPIClient(newTestPool(mcov1.MachineConfigPoolUpdated))
+
+	err := WaitForUpdateMultiple([]*MCPBuilder{NewMCPBuilder(apiClient, testPoolName), nil, {}}, time.Second)
+
+	var aggregateError *MCPAggregateError
+
+	if !errors.As(err, &aggregateError) {
+		t.Fatalf("expected an MCPAggregateError, got %v", err)
+	}
+
+	if len(aggregateError.Errors) != 2 {
+		t.Fatalf("expected an error for each invalid pool, got %v", aggregateError.Errors)
+	}
+
+	for _, poolError := range aggregateError.Errors {
+		if poolError.Pool != "<nil>" || poolError.Err == nil {
+			t.Errorf("expected only the invalid pools to fail, got %v", poolError)
+		}
+	}
+}
+
+func TestWaitForUpdateMultipleDuplicatePools(t *testing.T) {
+	apiClient, _ := newFakeAPIClient()
+
+	pools := []*MCPBuilder{
+		NewMCPBuilder(apiClient, "missing").WithPollInterval(10 * time.Millisecond),
+		NewMCPBuilder(apiClient, "missing").WithPollInterval(10 * time.Millisecond),
+	}
+
+	var aggregateError *MCPAggregateError
+
+	if err := WaitForUpdateMultiple(pools, 50*time.Millisecond); !errors.As(err, &aggregateError) {
+		t.Fatalf("expected an MCPAggregateError, got %v", err)
+	}
+
+	if len(aggregateError.Errors) != 2 {
+		t.Errorf("expected an error for each pool sharing the name, got %v", aggregateError.Errors)
+	}
+}
+
+func TestMCPBuilderWaitForEmptyRequestError(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+	mcpClient.getErr = k8serrors.NewServerTimeout(mcov1.Resource("machineconfigpools"), "get", 1)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForEmpty(50 * time.Millisecond); err == nil {
+		t.Error("expected WaitForEmpty to fail while the pool cannot be fetched")
+	}
+}
+
+func TestMCPBuilderWaitForConditionStatusRequestError(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+	mcpClient.getErr = k8serrors.NewServerTimeout(mcov1.Resource("machineconfigpools"), "get", 1)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	err := builder.WaitForConditionStatus(mcov1.MachineConfigPoolDegraded, corev1.ConditionFalse, 50*time.Millisecond)
+	if err == nil {
+		t.Error("expected WaitForConditionStatus to fail while the pool cannot be fetched")
+	}
+}
+
+func TestMCPBuilderWaitForDeletionWatch(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+	mcpClient.watcher = watch.NewFake()
+
+	go mcpClient.watcher.Delete(newTestPool())
+
+	if err := NewMCPBuilder(apiClient, testPoolName).WaitForDeletionWatch(time.Second); err != nil {
+		t.Errorf("expected the deletion event to be observed, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForDeletionWatchFallback(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	// the existence check sees the pool, the first poll of the fallback does not.
+	mcpClient.reactOnGet(2, func(pools map[string]*mcov1.MachineConfigPool) {
+		delete(pools, testPoolName)
+	})
+
+	if err := builder.WaitForDeletionWatch(time.Second); err != nil {
+		t.Errorf("expected the deletion to be observed by polling, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForDeletionWatchRequestError(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+	mcpClient.getErr = k8serrors.NewForbidden(mcov1.Resource("machineconfigpools"), testPoolName, errors.New("denied"))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForDeletionWatch(50 * time.Millisecond); !k8serrors.IsForbidden(err) {
+		t.Errorf("expected the forbidden error of the existence check, got %v", err)
+	}
+
+	// the existence check succeeds, every Get of the polling fallback fails.
+	mcpClient.getErrAfter = mcpClient.getCalls + 1
+
+	if err := builder.WaitForDeletionWatch(50 * time.Millisecond); err == nil {
+		t.Error("expected WaitForDeletionWatch to time out while the pool cannot be fetched")
+	}
+}
+
+func TestMCPBuilderDeleteRequestError(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+	mcpClient.getErr = k8serrors.NewServerTimeout(mcov1.Resource("machineconfigpools"), "get", 1)
+
+	if err := NewMCPBuilder(apiClient, testPoolName).Delete(); !k8serrors.IsServerTimeout(err) {
+		t.Errorf("expected Delete to return the request error, got %v", err)
+	}
+
+	if _, err := Pull(apiClient, testPoolName); !k8serrors.IsServerTimeout(err) {
+		t.Errorf("expected Pull to return the request error, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitToBeStableForRequestError(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+	mcpClient.getErr = k8serrors.NewServerTimeout(mcov1.Resource("machineconfigpools"), "get", 1)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	report, err := builder.WaitToBeStableForReport(30*time.Millisecond, 100*time.Millisecond)
+	if err == nil || report.Stable {
+		t.Errorf("expected a pool that cannot be fetched not to be stable, got %v", report)
+	}
+}
+
+func TestMCPAggregateErrorMatchesPoolErrors(t *testing.T) {
+	notFoundErr := k8serrors.NewNotFound(mcov1.Resource("machineconfigpools"), testPoolName)
+	aggregateError := &MCPAggregateError{
+		Operation: "test",
+		Errors: []MCPPoolError{
+			{Pool: "first", Err: errors.New("first failure")},
+			{Pool: testPoolName, Err: fmt.Errorf("wrapped: %w", notFoundErr)},
+		},
+	}
+
+	if !aggregateError.Is(notFoundErr) {
+		t.Error("expected Is to match the error of a failed pool")
+	}
+
+	var statusErr *k8serrors.StatusError
+
+	if !aggregateError.As(&statusErr) || !k8serrors.IsNotFound(statusErr) {
+		t.Errorf("expected As to find the status error of a failed pool, got %v", statusErr)
+	}
+
+	if aggregateError.Is(context.DeadlineExceeded) {
+		t.Error("expected Is not to match an error of no failed pool")
+	}
+}
+
+func TestMCPBuilderCreateOrUpdate(t *testing.T) {
+	existingPool := newTestPool()
+	existingPool.ResourceVersion = "1"
+	apiClient, mcpClient := newFakeAPIClient(existingPool)
+
+	var operations []string
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithMcSelector(map[string]string{"role": "test"}).
+		WithObserver(func(operation string, _ time.Duration, _ error) {
+			operations = append(operations, operation)
+		})
+
+	if _, err := builder.CreateOrUpdate(); err != nil {
+		t.Fatalf("expected the existing pool to be updated, got %v", err)
+	}
+
+	if mcpClient.pools[testPoolName].Spec.MachineConfigSelector == nil {
+		t.Error("expected the definition to be applied to the existing pool")
+	}
+
+	if !slices.Contains(operations, "CreateOrUpdate") {
+		t.Errorf("expected the CreateOrUpdate operation to be observed, got %v", operations)
+	}
+
+	if _, err := builder.WithResourceVersion("1").CreateOrUpdate(); !k8serrors.IsConflict(err) {
+		t.Errorf("expected a stale resourceVersion to conflict, got %v", err)
+	}
+}
+
+func TestMCPBuilderCreateOrUpdateRequestError(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient()
+	mcpClient.getErr = k8serrors.NewServerTimeout(mcov1.Resource("machineconfigpools"), "get", 1)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithMcSelector(map[string]string{"role": "test"})
+
+	if _, err := builder.CreateOrUpdate(); !k8serrors.IsServerTimeout(err) {
+		t.Errorf("expected CreateOrUpdate to return the request error, got %v", err)
+	}
+
+	if len(mcpClient.pools) != 0 {
+		t.Error("expected no pool to be created after a failed request")
+	}
+}
+
+func TestIsStableStatus(t *testing.T) {
+	testCases := []struct {
+		status      mcov1.MachineConfigPoolStatus
+		maxDegraded int32
+		stable      bool
+	}{
+		{status: mcov1.MachineConfigPoolStatus{MachineCount: 3, ReadyMachineCount: 3, UpdatedMachineCount: 3}, stable: true},
+		{status: mcov1.MachineConfigPoolStatus{MachineCount: 3, ReadyMachineCount: 4, UpdatedMachineCount: 3}},
+		{status: mcov1.MachineConfigPoolStatus{MachineCount: 3, ReadyMachineCount: 3, UpdatedMachineCount: 2}},
+		{
+			status: mcov1.MachineConfigPoolStatus{
+				MachineCount: 3, ReadyMachineCount: 2, UpdatedMachineCount: 2, DegradedMachineCount: 1},
+			maxDegraded: 1,
+			stable:      true,
+		},
+		{
+			status: mcov1.MachineConfigPoolStatus{
+				MachineCount: 3, ReadyMachineCount: 1, UpdatedMachineCount: 1, DegradedMachineCount: 2},
+			maxDegraded: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if stable := isStableStatus(&testCase.status, testCase.maxDegraded); stable != testCase.stable {
+			t.Errorf("expected status %+v with up to %d degraded machines to be stable %t, got %t",
+				testCase.status, testCase.maxDegraded, testCase.stable, stable)
+		}
+	}
+}
+
+func TestMCPBuilderIsCustomPool(t *testing.T) {
+	testCases := []struct {
+		name   string
+		labels map[string]string
+		custom bool
+	}{
+		{name: "worker"},
+		{name: "master"},
+		{name: "infra", custom: true},
+		{name: "infra", labels: map[string]string{poolOwnershipLabelPrefix + "worker": ""}, custom: true},
+		{name: "infra", labels: map[string]string{poolOwnershipLabelPrefix + "infra": ""}},
+		{name: "infra", labels: map[string]string{builtInPoolLabel: ""}},
+	}
+
+	apiClient, _ := newFakeAPIClient()
+
+	for _, testCase := range testCases {
+		builder := NewMCPBuilder(apiClient, testCase.name)
+		builder.Definition.Labels = testCase.labels
+
+		if custom := builder.IsCustomPool(); custom != testCase.custom {
+			t.Errorf("expected pool %s with labels %v to be custom %t, got %t",
+				testCase.name, testCase.labels, testCase.custom, custom)
+		}
+	}
+}
+
+func TestDeleteAllCustomPools(t *testing.T) {
+	newPools := func() []*mcov1.MachineConfigPool {
+		return []*mcov1.MachineConfigPool{
+			{ObjectMeta: metav1.ObjectMeta{Name: masterPoolName}},
+			{ObjectMeta: metav1.ObjectMeta{Name: workerPoolName}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "built-in", Labels: map[string]string{builtInPoolLabel: ""}}},
+			{ObjectMeta: metav1.ObjectMeta{
+				Name: "owned", Labels: map[string]string{poolOwnershipLabelPrefix + "owned": ""}}},
+			{ObjectMeta: metav1.ObjectMeta{
+				Name: "inheriting", Labels: map[string]string{poolOwnershipLabelPrefix + workerPoolName: ""}}},
+			newTestPool(),
+		}
+	}
+
+	deleteFuncs := map[string]func(apiClient *clients.Settings) error{
+		"DeleteAllCustomPools": DeleteAllCustomPools,
+		"DeleteAllCustomPoolsAndWait": func(apiClient *clients.Settings) error {
+			return DeleteAllCustomPoolsAndWait(apiClient, time.Second)
+		},
+	}
+
+	for name, deleteFunc := range deleteFuncs {
+		apiClient, mcpClient := newFakeAPIClient(newPools()...)
+
+		if err := deleteFunc(apiClient); err != nil {
+			t.Fatalf("%s: expected the custom pools to be deleted, got %v", name, err)
+		}
+
+		var remaining []string
+
+		for poolName := range mcpClient.pools {
+			remaining = append(remaining, poolName)
+		}
+
+		sort.Strings(remaining)
+
+		if expected := []string{"built-in", masterPoolName, "owned", workerPoolName}; !slices.Equal(remaining, expected) {
+			t.Errorf("%s: expected the pools %v to remain, got %v", name, expected, remaining)
+		}
+	}
+
+	apiClient, _ := newFakeAPIClient(newPools()...)
+
+	if err := DeleteAllCustomPoolsAndWait(apiClient, 0); err == nil {
+		t.Error("expected DeleteAllCustomPoolsAndWait to reject a zero timeout")
+	}
+}
+
+func TestMCPBuilderValidateDefinition(t *testing.T) {
+	apiClient, _ := newFakeAPIClient()
+
+	testCases := []struct {
+		builder *MCPBuilder
+		valid   bool
+	}{
+		{builder: NewMCPBuilder(apiClient, testPoolName).WithMcSelector(map[string]string{"role": "test"}), valid: true},
+		{builder: NewMCPBuilder(apiClient, testPoolName).WithEmptyMcSelector(), valid: true},
+		{builder: NewMCPBuilder(apiClient, testPoolName)},
+		{builder: NewMCPBuilder(apiClient, "Invalid_Name").WithMcSelector(map[string]string{"role": "test"})},
+		{builder: NewMCPBuilder(apiClient, testPoolName).WithMcSelectorFull(metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "role", Operator: "Unknown"}},
+		})},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.builder.ValidateDefinition()
+		if (err == nil) != testCase.valid {
+			t.Errorf("expected definition %v to be valid %t, got %v",
+				testCase.builder.Definition, testCase.valid, err)
+		}
+	}
+}
+
+func TestMCPBuilderWithFinalizer(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient()
+
+	for _, finalizer := range []string{"", "no-domain", "example.com/in valid"} {
+		builder := NewMCPBuilder(apiClient, testPoolName).WithFinalizer(finalizer)
+		if builder.errorMsg == "" {
+			t.Errorf("expected finalizer %q to be rejected", finalizer)
+		}
+	}
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithMcSelector(map[string]string{"role": "test"}).
+		WithFinalizer("example.com/cleanup").WithFinalizer("example.com/cleanup")
+
+	if _, err := builder.Create(); err != nil {
+		t.Fatalf("expected the pool to be created, got %v", err)
+	}
+
+	finalizers := mcpClient.pools[testPoolName].Finalizers
+	if len(finalizers) != 1 || finalizers[0] != "example.com/cleanup" {
+		t.Errorf("expected the created pool to carry the finalizer once, got %v", finalizers)
+	}
+}
+
+func TestMCPBuilderSetMaxUnavailable(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+
+	builder := NewMCPBuilder(apiClient, testPoolName)
+
+	for _, value := range []intstr.IntOrString{intstr.FromInt(0), intstr.FromString("many")} {
+		if err := builder.SetMaxUnavailable(value); err == nil {
+			t.Errorf("expected maxUnavailable %s to be rejected", value.String())
+		}
+	}
+
+	if err := builder.SetMaxUnavailable(intstr.FromString("25%")); err != nil {
+		t.Fatalf("expected maxUnavailable to be set, got %v", err)
+	}
+
+	maxUnavailable := mcpClient.pools[testPoolName].Spec.MaxUnavailable
+	if maxUnavailable == nil || maxUnavailable.String() != "25%" {
+		t.Errorf("expected the pool maxUnavailable to be 25%%, got %v", maxUnavailable)
+	}
+
+	if err := NewMCPBuilder(apiClient, "missing").SetMaxUnavailable(intstr.FromInt(2)); err == nil {
+		t.Error("expected SetMaxUnavailable to fail for a missing pool")
+	}
+}
+
+func TestMCPBuilderGetEffectiveMaxUnavailable(t *testing.T) {
+	testCases := []struct {
+		maxUnavailable *intstr.IntOrString
+		machineCount   int32
+		expected       int32
+	}{
+		{machineCount: 3, expected: 1},
+		{maxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 2}, machineCount: 3, expected: 2},
+		{maxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "50%"}, machineCount: 5, expected: 2},
+		{maxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "10%"}, machineCount: 3, expected: 1},
+	}
+
+	for _, testCase := range testCases {
+		mcp := newTestPool()
+		mcp.Spec.MaxUnavailable = testCase.maxUnavailable
+		mcp.Status.MachineCount = testCase.machineCount
+
+		apiClient, _ := newFakeAPIClient(mcp)
+
+		machines, err := NewMCPBuilder(apiClient, testPoolName).GetEffectiveMaxUnavailable()
+		if err != nil || machines != testCase.expected {
+			t.Errorf("expected maxUnavailable %v of %d machines to be %d, got %d, %v",
+				testCase.maxUnavailable, testCase.machineCount, testCase.expected, machines, err)
+		}
+	}
+}
+
+func TestMCPBuilderWaitToBeInConditionWatch(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+	mcpClient.watcher = watch.NewFake()
+
+	go mcpClient.watcher.Modify(newTestPool(mcov1.MachineConfigPoolDegraded))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	err := builder.WaitToBeInConditionWatch(mcov1.MachineConfigPoolDegraded, corev1.ConditionTrue, time.Second)
+	if err != nil {
+		t.Errorf("expected the condition to be observed by the watch, got %v", err)
+	}
+
+	mcpClient.watcher = watch.NewFake()
+
+	go mcpClient.watcher.Delete(newTestPool())
+
+	err = builder.WaitToBeInConditionWatch(mcov1.MachineConfigPoolUpdated, corev1.ConditionTrue, time.Second)
+	if err == nil {
+		t.Error("expected WaitToBeInConditionWatch to fail when the pool is deleted")
+	}
+}
+
+func TestMCPBuilderWaitToBeInConditionWatchFallback(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool())
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) {
+		mcp.Status = newTestPool(mcov1.MachineConfigPoolDegraded).Status
+	})
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	err := builder.WaitToBeInConditionWatch(mcov1.MachineConfigPoolDegraded, corev1.ConditionTrue, time.Second)
+	if err != nil {
+		t.Errorf("expected the condition to be observed by polling, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForUpdateWatch(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool(mcov1.MachineConfigPoolUpdating))
+	mcpClient.watcher = watch.NewFake()
+
+	go mcpClient.watcher.Modify(newTestPool(mcov1.MachineConfigPoolUpdated))
+
+	if err := NewMCPBuilder(apiClient, testPoolName).WaitForUpdateWatch(time.Second); err != nil {
+		t.Errorf("expected the update to be observed by the watch, got %v", err)
+	}
+
+	mcpClient.watcher = nil
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForUpdateWatch(50 * time.Millisecond); err == nil {
+		t.Error("expected WaitForUpdateWatch to time out while the pool is updating")
+	}
+}
+
+func TestMCPBuilderWaitForUpdatedMachineCount(t *testing.T) {
+	mcp := newTestPool()
+	mcp.Status.MachineCount = 3
+	mcp.Status.UpdatedMachineCount = 1
+	apiClient, mcpClient := newFakeAPIClient(mcp)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForUpdatedMachineCount(-1, time.Second); err == nil {
+		t.Error("expected a negative count to be rejected")
+	}
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) { mcp.Status.UpdatedMachineCount = 3 })
+
+	if err := builder.WaitForUpdatedMachineCount(3, time.Second); err != nil {
+		t.Errorf("expected all machines to be updated, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForReadyMachineCount(t *testing.T) {
+	mcp := newTestPool()
+	mcp.Status.MachineCount = 3
+	mcp.Status.ReadyMachineCount = 2
+	apiClient, mcpClient := newFakeAPIClient(mcp)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForReadyMachineCount(3, 50*time.Millisecond); err == nil {
+		t.Error("expected WaitForReadyMachineCount to time out with a machine not ready")
+	}
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) { mcp.Status.ReadyMachineCount = 3 })
+
+	if err := builder.WaitForReadyMachineCount(3, time.Second); err != nil {
+		t.Errorf("expected all machines to be ready, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForEmpty(t *testing.T) {
+	mcp := newTestPool()
+	mcp.Status.MachineCount = 1
+	apiClient, mcpClient := newFakeAPIClient(mcp)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) { mcp.Status.MachineCount = 0 })
+
+	if err := builder.WaitForEmpty(time.Second); err != nil {
+		t.Errorf("expected the pool to become empty, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForMachineCountChange(t *testing.T) {
+	mcp := newTestPool()
+	mcp.Status.MachineCount = 2
+	apiClient, mcpClient := newFakeAPIClient(mcp)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) { mcp.Status.MachineCount = 3 })
+
+	machineCount, err := builder.WaitForMachineCountChange(2, time.Second)
+	if err != nil || machineCount != 3 {
+		t.Errorf("expected the machine count to change to 3, got %d, %v", machineCount, err)
+	}
+}
+
+func TestMCPBuilderWaitForConfigurationName(t *testing.T) {
+	mcp := newTestPool()
+	mcp.Status.Configuration.Name = "rendered-test-1"
+	apiClient, mcpClient := newFakeAPIClient(mcp)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForConfigurationName("", time.Second); err == nil {
+		t.Error("expected an empty configuration name to be rejected")
+	}
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) { mcp.Status.Configuration.Name = "rendered-test-2" })
+
+	if err := builder.WaitForConfigurationName("rendered-test-2", time.Second); err != nil {
+		t.Errorf("expected the pool to run the new configuration, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForObservedGeneration(t *testing.T) {
+	mcp := newTestPool()
+	mcp.Status.ObservedGeneration = 1
+	apiClient, mcpClient := newFakeAPIClient(mcp)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForObservedGeneration(0, time.Second); err == nil {
+		t.Error("expected a non positive generation to be rejected")
+	}
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) { mcp.Status.ObservedGeneration = 2 })
+
+	if err := builder.WaitForObservedGeneration(2, time.Second); err != nil {
+		t.Errorf("expected the generation to be observed, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForConditionStatus(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool(mcov1.MachineConfigPoolDegraded))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) {
+		mcp.Status.Conditions[0].Status = corev1.ConditionFalse
+	})
+
+	err := builder.WaitForConditionStatus(mcov1.MachineConfigPoolDegraded, corev1.ConditionFalse, time.Second)
+	if err != nil {
+		t.Errorf("expected the condition status to be observed, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForDegradedThenRecover(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool(mcov1.MachineConfigPoolDegraded))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) {
+		mcp.Status.Conditions[0].Status = corev1.ConditionFalse
+	})
+
+	if err := builder.WaitForDegradedThenRecover(time.Second, time.Second); err != nil {
+		t.Errorf("expected the pool to degrade and recover, got %v", err)
+	}
+
+	if err := builder.WaitForDegradedThenRecover(50*time.Millisecond, time.Second); err == nil {
+		t.Error("expected WaitForDegradedThenRecover to fail on a pool that does not degrade")
+	}
+}
+
+func TestMCPBuilderWaitForUpdateDefault(t *testing.T) {
+	apiClient, _ := newFakeAPIClient(newTestPool(mcov1.MachineConfigPoolUpdated))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForUpdateDefault(); err == nil {
+		t.Error("expected WaitForUpdateDefault to fail without a default timeout")
+	}
+
+	if err := builder.WithDefaultTimeout(time.Second).WaitForUpdateDefault(); err != nil {
+		t.Errorf("expected the pool to be updated, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForUpdateWithProgress(t *testing.T) {
+	mcp := newTestPool(mcov1.MachineConfigPoolUpdating)
+	mcp.Status.MachineCount = 2
+	apiClient, mcpClient := newFakeAPIClient(mcp)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForUpdateWithProgress(time.Second, nil); err == nil {
+		t.Error("expected a nil onProgress function to be rejected")
+	}
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) {
+		mcp.Status = newTestPool(mcov1.MachineConfigPoolUpdated).Status
+		mcp.Status.MachineCount = 2
+		mcp.Status.UpdatedMachineCount = 2
+	})
+
+	var lastUpdated, lastTotal int32
+
+	err := builder.WaitForUpdateWithProgress(time.Second, func(updated, total int32) {
+		lastUpdated, lastTotal = updated, total
+	})
+	if err != nil {
+		t.Fatalf("expected the pool to be updated, got %v", err)
+	}
+
+	if lastUpdated != 2 || lastTotal != 2 {
+		t.Errorf("expected the last progress to be 2 of 2 machines, got %d of %d", lastUpdated, lastTotal)
+	}
+}
+
+// newStablePool returns a MachineConfigPool named testPoolName with the given machine counts and the Updated
+// condition set to True.
+func newStablePool(machineCount, readyMachineCount, degradedMachineCount int32) *mcov1.MachineConfigPool {
+	mcp := newTestPool(mcov1.MachineConfigPoolUpdated)
+	mcp.Status.MachineCount = machineCount
+	mcp.Status.ReadyMachineCount = readyMachineCount
+	mcp.Status.UpdatedMachineCount = readyMachineCount
+	mcp.Status.DegradedMachineCount = degradedMachineCount
+
+	return mcp
+}
+
+func TestMCPBuilderWaitForUpdateThenStable(t *testing.T) {
+	apiClient, _ := newFakeAPIClient(newStablePool(3, 3, 0))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForUpdateThenStable(50*time.Millisecond, time.Second); err != nil {
+		t.Errorf("expected the pool to be updated and stable, got %v", err)
+	}
+
+	apiClient, _ = newFakeAPIClient(newStablePool(3, 2, 0))
+
+	builder = NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForUpdateThenStable(50*time.Millisecond, 200*time.Millisecond); err == nil {
+		t.Error("expected WaitForUpdateThenStable to fail with a machine not ready")
+	}
+}
+
+func TestMCPBuilderWaitForStableAtGeneration(t *testing.T) {
+	mcp := newStablePool(3, 3, 0)
+	mcp.Status.ObservedGeneration = 2
+	apiClient, _ := newFakeAPIClient(mcp)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForStableAtGeneration(2, 50*time.Millisecond, time.Second); err != nil {
+		t.Errorf("expected the pool to be stable at generation 2, got %v", err)
+	}
+
+	if err := builder.WaitForStableAtGeneration(3, 50*time.Millisecond, 100*time.Millisecond); err == nil {
+		t.Error("expected WaitForStableAtGeneration to fail before the generation is observed")
+	}
+}
+
+func TestMCPBuilderWaitToBeStableWithTolerance(t *testing.T) {
+	apiClient, _ := newFakeAPIClient(newStablePool(3, 2, 1))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitToBeStableWithTolerance(-1, 50*time.Millisecond, time.Second); err == nil {
+		t.Error("expected a negative tolerance to be rejected")
+	}
+
+	if err := builder.WaitToBeStableWithTolerance(1, 50*time.Millisecond, time.Second); err != nil {
+		t.Errorf("expected one degraded machine to be tolerated, got %v", err)
+	}
+
+	if err := builder.WaitToBeStableWithTolerance(0, 50*time.Millisecond, 200*time.Millisecond); err == nil {
+		t.Error("expected a degraded machine not to be tolerated without tolerance")
+	}
+}
+
+func TestMCPBuilderWaitForNoUpdatingNodes(t *testing.T) {
+	apiClient, _ := newFakeAPIClient(newTestPoolWithNodeSelector(2))
+	withFakeNodes(apiClient,
+		newTestNode("node-0", corev1.ConditionTrue, map[string]string{
+			daemonconsts.MachineConfigDaemonStateAnnotationKey: daemonconsts.MachineConfigDaemonStateDone}),
+		newTestNode("node-1", corev1.ConditionTrue, map[string]string{
+			daemonconsts.MachineConfigDaemonStateAnnotationKey: daemonconsts.MachineConfigDaemonStateWorking}))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForNoUpdatingNodes(50 * time.Millisecond); err == nil {
+		t.Error("expected WaitForNoUpdatingNodes to time out with a node updating")
+	}
+
+	withFakeNodes(apiClient, newTestNode("node-0", corev1.ConditionTrue, nil))
+
+	if err := builder.WaitForNoUpdatingNodes(time.Second); err != nil {
+		t.Errorf("expected no node to be updating, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForMachineCountToMatchNodes(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPoolWithNodeSelector(1))
+	withFakeNodes(apiClient,
+		newTestNode("node-0", corev1.ConditionTrue, nil), newTestNode("node-1", corev1.ConditionTrue, nil))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	err := builder.WaitForMachineCountToMatchNodes(50 * time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "machineCount 1 does not match the 2 nodes") {
+		t.Errorf("expected a machineCount mismatch error, got %v", err)
+	}
+
+	mcpClient.updatePoolOnGet(2, func(mcp *mcov1.MachineConfigPool) { mcp.Status.MachineCount = 2 })
+
+	if err := builder.WaitForMachineCountToMatchNodes(time.Second); err != nil {
+		t.Errorf("expected machineCount to match the nodes, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForNodesReady(t *testing.T) {
+	apiClient, _ := newFakeAPIClient(newTestPoolWithNodeSelector(2))
+	withFakeNodes(apiClient,
+		newTestNode("node-0", corev1.ConditionTrue, nil), newTestNode("node-1", corev1.ConditionFalse, nil))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForNodesReady(50 * time.Millisecond); err == nil {
+		t.Error("expected WaitForNodesReady to time out with a node not Ready")
+	}
+
+	withFakeNodes(apiClient,
+		newTestNode("node-0", corev1.ConditionTrue, nil), newTestNode("node-1", corev1.ConditionTrue, nil))
+
+	if err := builder.WaitForNodesReady(time.Second); err != nil {
+		t.Errorf("expected all nodes to be Ready, got %v", err)
+	}
+}
+
+func TestMCPBuilderWaitForUpdateAndVerify(t *testing.T) {
+	apiClient, _ := newFakeAPIClient(newTestPoolWithNodeSelector(2))
+	withFakeNodes(apiClient,
+		newTestNode("node-0", corev1.ConditionTrue, nil), newTestNode("node-1", corev1.ConditionFalse, nil))
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	if err := builder.WaitForUpdateAndVerify(nil, time.Second); err == nil {
+		t.Error("expected an error with a nil verify function")
+	}
+
+	var verifiedNodes []string
+
+	err := builder.WaitForUpdateAndVerify(func(node *corev1.Node) error {
+		verifiedNodes = append(verifiedNodes, node.Name)
+
+		if node.Status.Conditions[0].Status != corev1.ConditionTrue {
+			return fmt.Errorf("not ready")
+		}
+
+		return nil
+	}, time.Second)
+
+	if err == nil || !strings.Contains(err.Error(), "node-1: not ready") || strings.Contains(err.Error(), "node-0") {
+		t.Errorf("expected only node-1 to fail verification, got %v", err)
+	}
+
+	if len(verifiedNodes) != 2 {
+		t.Errorf("expected every node to be verified, got %v", verifiedNodes)
+	}
+
+	err = builder.WaitForUpdateAndVerify(func(*corev1.Node) error { return nil }, time.Second)
+	if err != nil {
+		t.Errorf("expected verification to pass, got %v", err)
+	}
+}
+
+func TestWaitForNoDegradedPools(t *testing.T) {
+	if err := WaitForNoDegradedPools(nil, time.Second); err == nil {
+		t.Error("expected an error with a nil apiClient")
+	}
+
+	degradedPool := newTestPool()
+	degradedPool.Name = "degraded-pool"
+	degradedPool.Status.Conditions = []mcov1.MachineConfigPoolCondition{{
+		Type:    mcov1.MachineConfigPoolDegraded,
+		Status:  corev1.ConditionTrue,
+		Reason:  "NodeDegraded",
+		Message: "node-0 is degraded",
+	}}
+
+	apiClient, mcpClient := newFakeAPIClient(newTestPool(mcov1.MachineConfigPoolUpdated), degradedPool)
+
+	err := WaitForNoDegradedPools(apiClient, 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "degraded-pool (NodeDegraded: node-0 is degraded)") ||
+		strings.Contains(err.Error(), testPoolName) {
+		t.Errorf("expected the error to list only the degraded pool, got %v", err)
+	}
+
+	mcpClient.deletePool("degraded-pool")
+
+	if err := WaitForNoDegradedPools(apiClient, time.Second); err != nil {
+		t.Errorf("expected no pool to be degraded, got %v", err)
+	}
+}
+
+func TestMCPBuilderValidateForVersion(t *testing.T) {
+	apiClient, _ := newFakeAPIClient()
+
+	builder := NewMCPBuilderFromUnstructured(apiClient, &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "MachineConfigPool",
+		"metadata": map[string]interface{}{"name": testPoolName},
+		"spec": map[string]interface{}{
+			"pinnedImageSets": []interface{}{map[string]interface{}{"name": "test-images"}},
+		},
+	}})
+
+	err := builder.ValidateForVersion("4.15.3")
+	if err == nil || !strings.Contains(err.Error(), "'pinnedImageSets' requires OpenShift 4.16 or later") {
+		t.Errorf("expected pinnedImageSets to be rejected before 4.16, got %v", err)
+	}
+
+	if err := builder.ValidateForVersion("4.16"); err != nil {
+		t.Errorf("expected pinnedImageSets to be accepted on 4.16, got %v", err)
+	}
+
+	if err := NewMCPBuilder(apiClient, testPoolName).ValidateForVersion("4.12"); err != nil {
+		t.Errorf("expected a definition without version-gated fields to be accepted, got %v", err)
+	}
+
+	for _, version := range []string{"", "4", "4.x", "3.11", "4.16.0.1"} {
+		if err := builder.ValidateForVersion(version); err == nil {
+			t.Errorf("expected version %q to be rejected", version)
+		}
+	}
+}
+
+func TestMCPBuilderLabelMachineConfigForPool(t *testing.T) {
+	apiClient, _ := newFakeAPIClient()
+	mcClient := withFakeMachineConfigs(apiClient, &mcov1.MachineConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-config", ResourceVersion: "1"}})
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithMcSelector(map[string]string{"role": "test"})
+
+	if err := builder.LabelMachineConfigForPool(NewMCBuilder(apiClient, "test-config")); err != nil {
+		t.Fatalf("expected the existing MachineConfig to be labeled, got %v", err)
+	}
+
+	if mcLabels := mcClient.configs["test-config"].Labels; mcLabels["role"] != "test" {
+		t.Errorf("expected the MachineConfig on the cluster to carry the pool label, got %v", mcLabels)
+	}
+
+	if err := builder.LabelMachineConfigForPool(NewMCBuilder(apiClient, "missing-config")); err != nil {
+		t.Fatalf("expected a missing MachineConfig to be labeled in its definition only, got %v", err)
+	}
+
+	if _, found := mcClient.configs["missing-config"]; found || mcClient.writes != 1 {
+		t.Errorf("expected no write for a missing MachineConfig, got %d writes", mcClient.writes)
+	}
+
+	mcClient.getErr = k8serrors.NewServerTimeout(mcov1.Resource("machineconfigs"), "get", 1)
+
+	err := builder.LabelMachineConfigForPool(NewMCBuilder(apiClient, "test-config"))
+	if !k8serrors.IsServerTimeout(err) {
+		t.Errorf("expected the Get error to be returned, got %v", err)
+	}
+
+	if mcClient.writes != 1 {
+		t.Errorf("expected no write after a failed Get, got %d writes", mcClient.writes)
+	}
+}
+
+func TestMCPBuilderAssertNoRolloutWhilePaused(t *testing.T) {
+	apiClient, mcpClient := newFakeAPIClient(newTestPool(mcov1.MachineConfigPoolUpdated))
+	mcClient := withFakeMachineConfigs(apiClient)
+
+	builder := NewMCPBuilder(apiClient, testPoolName).WithPollInterval(10 * time.Millisecond)
+
+	err := builder.AssertNoRolloutWhilePaused(NewMCBuilder(apiClient, "test-config"), 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected no rollout while paused, got %v", err)
+	}
+
+	if _, found := mcClient.configs["test-config"]; !found {
+		t.Error("expected the MachineConfig to be created")
+	}
+
+	mcClient.getErr = k8serrors.NewServerTimeout(mcov1.Resource("machineconfigs"), "get", 1)
+	writes := mcClient.writes
+
+	err = builder.AssertNoRolloutWhilePaused(NewMCBuilder(apiClient, "test-config"), 50*time.Millisecond)
+	if !k8serrors.IsServerTimeout(err) {
+		t.Errorf("expected the Get error to be returned, got %v", err)
+	}
+
+	if mcClient.writes != writes {
+		t.Errorf("expected no write after a failed Get, got %d writes", mcClient.writes-writes)
+	}
+
+	if mcpClient.pools[testPoolName].Spec.Paused {
+		t.Error("expected the paused field of the pool to be restored")
+	}
+}
+
+func TestMCPBuilderGetLastAppliedConfigs(t *testing.T) {
+	mcp := newTestPoolWithNodeSelector(3)
+	mcp.Status.Configuration.Name = "rendered-test-pool-new"
+
+	apiClient, _ := newFakeAPIClient(mcp)
+	withFakeNodes(apiClient,
+		newTestNode("node-c", corev1.ConditionTrue, map[string]string{
+			daemonconsts.CurrentMachineConfigAnnotationKey: "rendered-test-pool-older"}),
+		newTestNode("node-a", corev1.ConditionTrue, map[string]string{
+			daemonconsts.CurrentMachineConfigAnnotationKey: "rendered-test-pool-new"}),
+		newTestNode("node-b", corev1.ConditionTrue, map[string]string{
+			daemonconsts.CurrentMachineConfigAnnotationKey: "rendered-test-pool-old"}))
+
+	builder := NewMCPBuilder(apiClient, testPoolName)
+
+	testCases := []struct {
+		limit    int
+		expected []string
+	}{
+		{limit: 1, expected: []string{"rendered-test-pool-new"}},
+		{limit: 2, expected: []string{"rendered-test-pool-new", "rendered-test-pool-old"}},
+		{limit: 5, expected: []string{"rendered-test-pool-new", "rendered-test-pool-old", "rendered-test-pool-older"}},
+	}
+
+	for _, testCase := range testCases {
+		configNames, err := builder.GetLastAppliedConfigs(testCase.limit)
+		if err != nil {
+			t.Fatalf("expected the applied configs to be returned, got %v", err)
+		}
+
+		if !slices.Equal(configNames, testCase.expected) {
+			t.Errorf("expected the last %d applied configs %v, got %v", testCase.limit, testCase.expected, configNames)
+		}
+	}
+
+	if _, err := builder.GetLastAppliedConfigs(0); err == nil {
+		t.Error("expected a zero limit to be rejected")
+	}
+}
@@ -0,0 +1,86 @@
+package mco
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "eco_goinfra"
+	metricsSubsystem = "mco"
+)
+
+var (
+	// metricsEnabled is set once the MachineConfigPool metrics are registered.
+	metricsEnabled atomic.Bool
+
+	mcpUpdateDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "mcp_update_duration_seconds",
+		Help:      "Duration of waiting for MachineConfigPools to be updated.",
+		Buckets:   prometheus.ExponentialBuckets(30, 2, 8),
+	}, []string{"pool", "result"})
+
+	mcpDegradedMachines = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "mcp_degraded_machines",
+		Help:      "Number of degraded machines last observed while waiting on a MachineConfigPool.",
+	}, []string{"pool"})
+)
+
+// RegisterMetrics registers the MachineConfigPool metrics with the given registry and starts populating them
+// when the MachineConfigPool builder wait methods run. Metrics are not collected unless registered.
+func RegisterMetrics(registry prometheus.Registerer) error {
+	verbose().Infof("Registering MachineConfigPool metrics")
+
+	if registry == nil {
+		verbose().Infof("The metrics registry is nil")
+
+		return fmt.Errorf("metrics 'registry' cannot be nil")
+	}
+
+	for _, collector := range []prometheus.Collector{mcpUpdateDuration, mcpDegradedMachines} {
+		err := registry.Register(collector)
+		if err == nil {
+			continue
+		}
+
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			return fmt.Errorf("failed to register MachineConfigPool metrics: %w", err)
+		}
+	}
+
+	metricsEnabled.Store(true)
+
+	return nil
+}
+
+// recordMetrics populates the MachineConfigPool metrics on completion of a builder operation.
+func recordMetrics(builder *MCPBuilder, operation string, duration time.Duration, err error) {
+	if !metricsEnabled.Load() || builder.Definition == nil || !strings.HasPrefix(operation, "Wait") {
+		return
+	}
+
+	poolName := builder.Definition.Name
+
+	if operation == "WaitForUpdate" || operation == "WaitForUpdateWatch" {
+		result := "success"
+		if err != nil {
+			result = "failure"
+		}
+
+		mcpUpdateDuration.WithLabelValues(poolName, result).Observe(duration.Seconds())
+	}
+
+	if builder.Object != nil {
+		mcpDegradedMachines.WithLabelValues(poolName).Set(float64(builder.Object.Status.DegradedMachineCount))
+	}
+}